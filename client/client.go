@@ -91,6 +91,8 @@ package client // import "go.opentelemetry.io/collector/client"
 import (
 	"context"
 	"net"
+	"sort"
+	"time"
 )
 
 type ctxKey struct{}
@@ -106,6 +108,11 @@ type Info struct {
 	// configauth.ServerAuthenticator implementations tied to the receiver for
 	// this connection.
 	Auth AuthData
+
+	// Deadline of the incoming request, as set by the client. Populated by the
+	// configauth gRPC server interceptors for authenticated requests. The zero
+	// value means that the request has no deadline.
+	Deadline time.Time
 }
 
 // AuthData represents the authentication data as seen by authenticators tied to
@@ -122,6 +129,33 @@ type AuthData interface {
 	GetAttributeNames() []string
 }
 
+// NewAuthData returns an AuthData holding the given attributes, such as the
+// authenticated subject, its scopes or tenant. Authenticators not needing a
+// specific implementation can use it to populate Info.Auth.
+func NewAuthData(attributes map[string]interface{}) AuthData {
+	attrs := make(map[string]interface{}, len(attributes))
+	for k, v := range attributes {
+		attrs[k] = v
+	}
+	return mapAuthData(attrs)
+}
+
+// mapAuthData is an AuthData backed by a map.
+type mapAuthData map[string]interface{}
+
+func (m mapAuthData) GetAttribute(name string) interface{} {
+	return m[name]
+}
+
+func (m mapAuthData) GetAttributeNames() []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // NewContext takes an existing context and derives a new context with the
 // client.Info value stored on it.
 func NewContext(ctx context.Context, c Info) context.Context {
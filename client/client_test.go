@@ -86,3 +86,19 @@ func TestFromContext(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAuthData(t *testing.T) {
+	attrs := map[string]interface{}{
+		"subject": "jdoe",
+		"scopes":  []string{"metrics:write", "traces:write"},
+	}
+	authData := NewAuthData(attrs)
+
+	// changes to the original map aren't reflected in the auth data
+	attrs["tenant"] = "acme"
+
+	assert.Equal(t, "jdoe", authData.GetAttribute("subject"))
+	assert.Equal(t, []string{"metrics:write", "traces:write"}, authData.GetAttribute("scopes"))
+	assert.Nil(t, authData.GetAttribute("tenant"))
+	assert.Equal(t, []string{"scopes", "subject"}, authData.GetAttributeNames())
+}
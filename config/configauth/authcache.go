@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth // import "go.opentelemetry.io/collector/config/configauth"
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/client"
+)
+
+// NewCachingAuthenticateFunc wraps the given AuthenticateFunc, caching the outcome of successful authentications for
+// the given TTL. The cache is keyed by the raw value of the headerName header, such as "authorization" or "x-api-key",
+// which should match the one set with WithHeaderName, if any. The name is case-insensitive, and "authorization" is
+// used when it is empty. Requests without this header always invoke the inner function. Failed authentications are
+// never cached.
+// As the context returned by the inner function belongs to the request that was authenticated, only the resulting
+// client.Info's Auth is cached, and it is set on the context of subsequent requests presenting the same credentials.
+// Anything else the inner function adds to the context is lost on cache hits, and authentication results depending
+// on other headers than headerName are shared by all the requests presenting the same credentials.
+// When the cache holds more than maxEntries entries, the oldest ones are evicted. A maxEntries of 0 or less means
+// the cache is unbounded.
+func NewCachingAuthenticateFunc(inner AuthenticateFunc, headerName string, ttl time.Duration, maxEntries int) AuthenticateFunc {
+	return newAuthCache(inner, headerName, ttl, maxEntries, time.Now).authenticate
+}
+
+// authCache is a concurrency-safe cache of authentication results, evicting the oldest entries first.
+type authCache struct {
+	inner      AuthenticateFunc
+	headerName string
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu sync.Mutex
+	// entries holds the *list.Element of each credential, whose values are *authCacheEntry ordered from oldest to newest.
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type authCacheEntry struct {
+	key       string
+	auth      client.AuthData
+	expiresAt time.Time
+}
+
+func newAuthCache(inner AuthenticateFunc, headerName string, ttl time.Duration, maxEntries int, now func() time.Time) *authCache {
+	if headerName == "" {
+		headerName = defaultHeaderName
+	}
+	return &authCache{
+		inner:      inner,
+		headerName: strings.ToLower(headerName),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *authCache) authenticate(ctx context.Context, headers map[string][]string) (context.Context, error) {
+	credentials, ok := headers[c.headerName]
+	if !ok || len(credentials) == 0 {
+		return c.inner(ctx, headers)
+	}
+	key := strings.Join(credentials, "\x00")
+
+	if auth, found := c.get(key); found {
+		cl := client.FromContext(ctx)
+		cl.Auth = auth
+		return client.NewContext(ctx, cl), nil
+	}
+
+	newCtx, err := c.inner(ctx, headers)
+	if err != nil {
+		return newCtx, err
+	}
+	c.put(key, client.FromContext(newCtx).Auth)
+	return newCtx, nil
+}
+
+func (c *authCache) get(key string) (client.AuthData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*authCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(elem)
+		return nil, false
+	}
+	return entry.auth, true
+}
+
+func (c *authCache) put(key string, auth client.AuthData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		// another request authenticated the same credentials concurrently, keep the most recent result
+		c.remove(elem)
+	}
+	c.entries[key] = c.order.PushBack(&authCacheEntry{
+		key:       key,
+		auth:      auth,
+		expiresAt: c.now().Add(c.ttl),
+	})
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Front())
+	}
+}
+
+// remove deletes the given element from the cache. The caller must hold the lock.
+func (c *authCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*authCacheEntry).key)
+}
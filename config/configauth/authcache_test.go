@@ -0,0 +1,208 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/client"
+)
+
+// countingAuthenticateFunc returns an AuthenticateFunc counting its calls, and setting the credential as the subject.
+func countingAuthenticateFunc(calls *int32) AuthenticateFunc {
+	return func(ctx context.Context, headers map[string][]string) (context.Context, error) {
+		atomic.AddInt32(calls, 1)
+		cred := headers["authorization"]
+		if len(cred) == 0 {
+			cred = headers["x-api-key"]
+		}
+		if len(cred) == 0 || cred[0] == "invalid" {
+			return ctx, errors.New("not authenticated")
+		}
+		cl := client.FromContext(ctx)
+		cl.Auth = client.NewAuthData(map[string]interface{}{"subject": cred[0]})
+		return client.NewContext(ctx, cl), nil
+	}
+}
+
+func authHeaders(cred string) map[string][]string {
+	return map[string][]string{"authorization": {cred}}
+}
+
+func TestCachingAuthenticateFuncHitAndMiss(t *testing.T) {
+	// prepare
+	var calls int32
+	authFunc := NewCachingAuthenticateFunc(countingAuthenticateFunc(&calls), "", time.Minute, 10)
+
+	// test and verify
+	ctx, err := authFunc(context.Background(), authHeaders("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", client.FromContext(ctx).Auth.GetAttribute("subject"))
+	assert.EqualValues(t, 1, calls)
+
+	// the cached auth data is set on the context of the new request
+	type key struct{}
+	reqCtx := context.WithValue(context.Background(), key{}, "second")
+	ctx, err = authFunc(reqCtx, authHeaders("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", client.FromContext(ctx).Auth.GetAttribute("subject"))
+	assert.Equal(t, "second", ctx.Value(key{}))
+	assert.EqualValues(t, 1, calls)
+
+	// other credentials are a miss
+	ctx, err = authFunc(context.Background(), authHeaders("bob"))
+	require.NoError(t, err)
+	assert.Equal(t, "bob", client.FromContext(ctx).Auth.GetAttribute("subject"))
+	assert.EqualValues(t, 2, calls)
+}
+
+func TestCachingAuthenticateFuncCustomHeader(t *testing.T) {
+	// prepare
+	var calls int32
+	authFunc := NewCachingAuthenticateFunc(countingAuthenticateFunc(&calls), "X-API-Key", time.Minute, 10)
+	headers := map[string][]string{"x-api-key": {"alice"}}
+
+	// test
+	for i := 0; i < 3; i++ {
+		ctx, err := authFunc(context.Background(), headers)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", client.FromContext(ctx).Auth.GetAttribute("subject"))
+	}
+
+	// verify
+	assert.EqualValues(t, 1, calls)
+}
+
+func TestCachingAuthenticateFuncIgnoresOtherHeaders(t *testing.T) {
+	// prepare
+	var calls int32
+	inner := func(ctx context.Context, headers map[string][]string) (context.Context, error) {
+		atomic.AddInt32(&calls, 1)
+		cl := client.FromContext(ctx)
+		cl.Auth = client.NewAuthData(map[string]interface{}{
+			"subject": headers["authorization"][0],
+			"tenant":  headers["x-tenant"][0],
+		})
+		return client.NewContext(ctx, cl), nil
+	}
+	authFunc := NewCachingAuthenticateFunc(inner, "", time.Minute, 10)
+
+	// test
+	ctx1, err := authFunc(context.Background(), map[string][]string{"authorization": {"alice"}, "x-tenant": {"acme"}})
+	require.NoError(t, err)
+	ctx2, err := authFunc(context.Background(), map[string][]string{"authorization": {"alice"}, "x-tenant": {"other"}})
+	require.NoError(t, err)
+
+	// verify: the cache is keyed by the credentials only, so the second request is served the first request's result
+	assert.EqualValues(t, 1, calls)
+	assert.Equal(t, "acme", client.FromContext(ctx1).Auth.GetAttribute("tenant"))
+	assert.Equal(t, "acme", client.FromContext(ctx2).Auth.GetAttribute("tenant"))
+}
+
+func TestCachingAuthenticateFuncDoesNotCacheFailures(t *testing.T) {
+	// prepare
+	var calls int32
+	authFunc := NewCachingAuthenticateFunc(countingAuthenticateFunc(&calls), "", time.Minute, 10)
+
+	// test
+	for i := 0; i < 3; i++ {
+		_, err := authFunc(context.Background(), authHeaders("invalid"))
+		assert.Error(t, err)
+	}
+	for i := 0; i < 3; i++ {
+		_, err := authFunc(context.Background(), map[string][]string{})
+		assert.Error(t, err)
+	}
+
+	// verify
+	assert.EqualValues(t, 6, calls)
+}
+
+func TestCachingAuthenticateFuncExpiry(t *testing.T) {
+	// prepare
+	var calls int32
+	now := time.Now()
+	cache := newAuthCache(countingAuthenticateFunc(&calls), "", time.Minute, 10, func() time.Time { return now })
+
+	// test and verify
+	_, err := cache.authenticate(context.Background(), authHeaders("alice"))
+	require.NoError(t, err)
+	now = now.Add(59 * time.Second)
+	_, err = cache.authenticate(context.Background(), authHeaders("alice"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, calls)
+
+	now = now.Add(time.Second)
+	_, err = cache.authenticate(context.Background(), authHeaders("alice"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, calls)
+}
+
+func TestCachingAuthenticateFuncEviction(t *testing.T) {
+	// prepare
+	var calls int32
+	authFunc := NewCachingAuthenticateFunc(countingAuthenticateFunc(&calls), "", time.Minute, 2)
+
+	// test
+	for _, cred := range []string{"alice", "bob", "carol"} {
+		_, err := authFunc(context.Background(), authHeaders(cred))
+		require.NoError(t, err)
+	}
+	require.EqualValues(t, 3, calls)
+
+	// verify: the oldest entry was evicted, the newest ones are still cached
+	for _, cred := range []string{"bob", "carol"} {
+		_, err := authFunc(context.Background(), authHeaders(cred))
+		require.NoError(t, err)
+	}
+	assert.EqualValues(t, 3, calls)
+	_, err := authFunc(context.Background(), authHeaders("alice"))
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, calls)
+}
+
+func TestCachingAuthenticateFuncConcurrentAccess(t *testing.T) {
+	// prepare
+	var calls int32
+	authFunc := NewCachingAuthenticateFunc(countingAuthenticateFunc(&calls), "", time.Minute, 10)
+
+	// test
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cred := fmt.Sprintf("user-%d", i%10)
+			for j := 0; j < 20; j++ {
+				ctx, err := authFunc(context.Background(), authHeaders(cred))
+				assert.NoError(t, err)
+				assert.Equal(t, cred, client.FromContext(ctx).Auth.GetAttribute("subject"))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// verify: each goroutine misses at most once, when its credentials aren't cached yet
+	assert.LessOrEqual(t, atomic.LoadInt32(&calls), int32(50))
+}
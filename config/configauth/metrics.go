@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth // import "go.opentelemetry.io/collector/config/configauth"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/unit"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+const (
+	meterName = "go.opentelemetry.io/collector/config/configauth"
+
+	authenticatorKey = attribute.Key("authenticator")
+	outcomeKey       = attribute.Key("outcome")
+	methodKey        = attribute.Key("method")
+	reasonKey        = attribute.Key("reason")
+
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+
+	reasonMissingMetadata  = "missing_metadata"
+	reasonMetadataTooLarge = "metadata_too_large"
+	reasonUnauthenticated  = "unauthenticated"
+	reasonUnauthorized     = "unauthorized"
+)
+
+// authMetrics holds the instruments recording the authentication attempts of a single authenticator.
+type authMetrics struct {
+	attempts  metric.Int64Counter
+	successes metric.Int64Counter
+	failures  metric.Int64Counter
+	duration  metric.Float64Histogram
+	id        attribute.KeyValue
+}
+
+// NewInstrumentedAuthenticateFunc wraps the given AuthenticateFunc so that each call is recorded as metrics using the
+// given MeterProvider: the number of attempts, successes and failures, as well as the duration of the authentication.
+// All metrics are labeled with the ID of the authenticator, and the duration is also labeled with the outcome.
+// This is typically used by ServerAuthenticator implementations, wrapping their own authentication function with the
+// MeterProvider from their component.TelemetrySettings.
+func NewInstrumentedAuthenticateFunc(authenticatorID config.ComponentID, inner AuthenticateFunc, mp metric.MeterProvider) (AuthenticateFunc, error) {
+	meter := mp.Meter(meterName)
+	m := &authMetrics{id: authenticatorKey.String(authenticatorID.String())}
+
+	var err error
+	if m.attempts, err = meter.NewInt64Counter("auth_attempts",
+		metric.WithDescription("Number of authentication attempts")); err != nil {
+		return nil, err
+	}
+	if m.successes, err = meter.NewInt64Counter("auth_successes",
+		metric.WithDescription("Number of successful authentications")); err != nil {
+		return nil, err
+	}
+	if m.failures, err = meter.NewInt64Counter("auth_failures",
+		metric.WithDescription("Number of failed authentications")); err != nil {
+		return nil, err
+	}
+	if m.duration, err = meter.NewFloat64Histogram("auth_duration",
+		metric.WithDescription("Duration of the authentication"),
+		metric.WithUnit(unit.Milliseconds)); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, headers map[string][]string) (context.Context, error) {
+		start := time.Now()
+		m.attempts.Add(ctx, 1, m.id)
+
+		newCtx, err := inner(ctx, headers)
+
+		outcome := outcomeSuccess
+		if err != nil {
+			outcome = outcomeFailure
+			m.failures.Add(ctx, 1, m.id)
+		} else {
+			m.successes.Add(ctx, 1, m.id)
+		}
+		m.duration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), m.id, outcomeKey.String(outcome))
+
+		return newCtx, err
+	}, nil
+}
+
+// interceptorMetrics holds the instruments recording the authentication decisions of the server interceptors.
+// A nil *interceptorMetrics records nothing.
+type interceptorMetrics struct {
+	success metric.Int64Counter
+	failure metric.Int64Counter
+}
+
+func newInterceptorMetrics(mp metric.MeterProvider) (*interceptorMetrics, error) {
+	meter := mp.Meter(meterName)
+	m := &interceptorMetrics{}
+
+	var err error
+	if m.success, err = meter.NewInt64Counter("auth_interceptor_successes",
+		metric.WithDescription("Number of requests accepted by the authentication interceptors")); err != nil {
+		return nil, err
+	}
+	if m.failure, err = meter.NewInt64Counter("auth_interceptor_failures",
+		metric.WithDescription("Number of requests rejected by the authentication interceptors")); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *interceptorMetrics) recordSuccess(ctx context.Context, fullMethod string) {
+	if m == nil {
+		return
+	}
+	m.success.Add(ctx, 1, methodKey.String(fullMethod))
+}
+
+func (m *interceptorMetrics) recordFailure(ctx context.Context, fullMethod string, reason string) {
+	if m == nil {
+		return
+	}
+	m.failure.Add(ctx, 1, methodKey.String(fullMethod), reasonKey.String(reason))
+}
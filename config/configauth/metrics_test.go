@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/metrictest"
+	"go.opentelemetry.io/otel/metric/sdkapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+func TestInstrumentedAuthenticateFunc(t *testing.T) {
+	// prepare
+	mp := metrictest.NewMeterProvider()
+	fail := false
+	inner := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+		if fail {
+			return ctx, errors.New("not authenticated")
+		}
+		return ctx, nil
+	}
+	authFunc, err := NewInstrumentedAuthenticateFunc(config.NewComponentIDWithName("oidc", "test"), inner, mp)
+	require.NoError(t, err)
+
+	// test
+	_, err = authFunc(context.Background(), nil)
+	assert.NoError(t, err)
+	fail = true
+	_, err = authFunc(context.Background(), nil)
+	assert.Error(t, err)
+
+	// verify
+	counts := map[string]int64{}
+	outcomes := map[string]int{}
+	for _, m := range metrictest.AsStructs(mp.MeasurementBatches) {
+		assert.Equal(t, "oidc/test", m.Labels[authenticatorKey].AsString())
+		if m.Name == "auth_duration" {
+			outcomes[m.Labels[outcomeKey].AsString()]++
+			continue
+		}
+		counts[m.Name] += m.Number.AsInt64()
+	}
+	assert.Equal(t, map[string]int64{
+		"auth_attempts":  2,
+		"auth_successes": 1,
+		"auth_failures":  1,
+	}, counts)
+	assert.Equal(t, map[string]int{
+		outcomeSuccess: 1,
+		outcomeFailure: 1,
+	}, outcomes)
+}
+
+func TestInterceptorMetrics(t *testing.T) {
+	const method = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+	testCases := []struct {
+		desc           string
+		md             metadata.MD
+		opts           []InterceptorOption
+		authErr        error
+		expectedName   string
+		expectedReason string
+	}{
+		{
+			desc:         "success",
+			md:           metadata.Pairs("authorization", "some-auth-data"),
+			expectedName: "auth_interceptor_successes",
+		},
+		{
+			desc:           "missing metadata",
+			expectedName:   "auth_interceptor_failures",
+			expectedReason: reasonMissingMetadata,
+		},
+		{
+			desc:           "metadata too large",
+			md:             metadata.Pairs("authorization", "some-auth-data"),
+			opts:           []InterceptorOption{WithMaxMetadataSize(1)},
+			expectedName:   "auth_interceptor_failures",
+			expectedReason: reasonMetadataTooLarge,
+		},
+		{
+			desc:           "authentication failure",
+			md:             metadata.Pairs("authorization", "some-auth-data"),
+			authErr:        errors.New("not authenticated"),
+			expectedName:   "auth_interceptor_failures",
+			expectedReason: reasonUnauthenticated,
+		},
+		{
+			desc:           "authorization failure",
+			md:             metadata.Pairs("authorization", "some-auth-data"),
+			opts:           []InterceptorOption{WithAuthorizeFunc(func(context.Context) error { return errors.New("forbidden") })},
+			expectedName:   "auth_interceptor_failures",
+			expectedReason: reasonUnauthorized,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			mp := metrictest.NewMeterProvider()
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				return ctx, tC.authErr
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			}
+			ctx := context.Background()
+			if tC.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tC.md)
+			}
+			opts := append([]InterceptorOption{WithMeterProvider(mp)}, tC.opts...)
+			interceptor, err := NewGRPCUnaryServerInterceptor(opts...)
+			require.NoError(t, err)
+
+			// test
+			_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler, authFunc)
+
+			// verify
+			measured := metrictest.AsStructs(mp.MeasurementBatches)
+			require.Len(t, measured, 1)
+			assert.Equal(t, tC.expectedName, measured[0].Name)
+			assert.EqualValues(t, 1, measured[0].Number.AsInt64())
+			assert.Equal(t, method, measured[0].Labels[methodKey].AsString())
+			assert.Equal(t, tC.expectedReason, measured[0].Labels[reasonKey].AsString())
+		})
+	}
+}
+
+func TestStreamInterceptorMetrics(t *testing.T) {
+	// prepare
+	mp := metrictest.NewMeterProvider()
+	authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+		return ctx, nil
+	}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+	interceptor, err := NewGRPCStreamServerInterceptor(WithMeterProvider(mp), WithExemptMethods("/grpc.health.v1.Health/Watch"))
+	require.NoError(t, err)
+
+	// test
+	assert.NoError(t, interceptor(nil, &mockServerStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data")),
+	}, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, handler, authFunc))
+	assert.Equal(t, errMetadataNotFound, interceptor(nil, &mockServerStream{
+		ctx: context.Background(),
+	}, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, handler, authFunc))
+	// exempt methods aren't authentication decisions
+	assert.NoError(t, interceptor(nil, &mockServerStream{
+		ctx: context.Background(),
+	}, &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}, handler, authFunc))
+
+	// verify
+	counts := map[string]int64{}
+	for _, m := range metrictest.AsStructs(mp.MeasurementBatches) {
+		assert.Equal(t, "/test/Stream", m.Labels[methodKey].AsString())
+		counts[m.Name] += m.Number.AsInt64()
+	}
+	assert.Equal(t, map[string]int64{
+		"auth_interceptor_successes": 1,
+		"auth_interceptor_failures":  1,
+	}, counts)
+}
+
+func TestInstrumentCreationFailure(t *testing.T) {
+	mp := failingMeterProvider{}
+
+	_, err := NewInstrumentedAuthenticateFunc(config.NewComponentID("oidc"), nil, mp)
+	assert.Error(t, err)
+
+	_, err = NewGRPCUnaryServerInterceptor(WithMeterProvider(mp))
+	assert.Error(t, err)
+
+	_, err = NewGRPCStreamServerInterceptor(WithMeterProvider(mp))
+	assert.Error(t, err)
+}
+
+// failingMeterProvider is a metric.MeterProvider whose instruments can't be created.
+type failingMeterProvider struct{}
+
+func (failingMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return metric.WrapMeterImpl(failingMeterImpl{})
+}
+
+type failingMeterImpl struct{}
+
+func (failingMeterImpl) RecordBatch(context.Context, []attribute.KeyValue, ...sdkapi.Measurement) {}
+
+func (failingMeterImpl) NewSyncInstrument(sdkapi.Descriptor) (sdkapi.SyncImpl, error) {
+	return nil, errors.New("instrument creation failed")
+}
+
+func (failingMeterImpl) NewAsyncInstrument(sdkapi.Descriptor, sdkapi.AsyncRunner) (sdkapi.AsyncImpl, error) {
+	return nil, errors.New("instrument creation failed")
+}
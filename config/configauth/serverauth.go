@@ -17,14 +17,22 @@ package configauth // import "go.opentelemetry.io/collector/config/configauth"
 import (
 	"context"
 	"errors"
+	"net/http"
+	"strings"
 
+	"go.opentelemetry.io/otel/metric"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
+	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/internal/middleware"
 )
 
+const defaultHeaderName = "authorization"
+
 var (
 	errMetadataNotFound = errors.New("no request metadata found")
 )
@@ -66,6 +74,11 @@ type ServerAuthenticator interface {
 // See ServerAuthenticator.Authenticate.
 type AuthenticateFunc func(ctx context.Context, headers map[string][]string) (context.Context, error)
 
+// AuthorizeFunc defines the signature for the function deciding whether an authenticated request is allowed to proceed.
+// It receives the context returned by the AuthenticateFunc, from which it can read the client.Info and its AuthData,
+// for instance to verify that the caller holds a required scope. A non-nil error rejects the request.
+type AuthorizeFunc func(ctx context.Context) error
+
 // GRPCUnaryInterceptorFunc defines the signature for the function intercepting unary gRPC calls, useful for authenticators to use as
 // types for internal structs, making it easier to mock them in tests.
 // See ServerAuthenticator.GRPCUnaryServerInterceptor.
@@ -76,15 +89,148 @@ type GRPCUnaryInterceptorFunc func(ctx context.Context, req interface{}, info *g
 // See ServerAuthenticator.GRPCStreamServerInterceptor.
 type GRPCStreamInterceptorFunc func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler, authenticate AuthenticateFunc) error
 
+// InterceptorOption configures the interceptors built by NewGRPCUnaryServerInterceptor and NewGRPCStreamServerInterceptor.
+type InterceptorOption func(opts *interceptorOptions)
+
+// interceptorOptions holds the settings shared by the unary and stream server interceptors.
+type interceptorOptions struct {
+	// headerName is the metadata key that must be present for the authenticate function to be invoked, or empty when
+	// any metadata is passed to the authenticate function.
+	headerName string
+	// maxMetadataSize is the maximum size in bytes of the credentials metadata, or 0 for no limit.
+	maxMetadataSize int
+	// exemptMethods holds the full method names for which authentication is skipped.
+	exemptMethods map[string]struct{}
+	// exemptMethodPrefixes holds the full method name prefixes for which authentication is skipped.
+	exemptMethodPrefixes []string
+	// authorize is invoked after a successful authentication, when set.
+	authorize AuthorizeFunc
+	// meterProvider is used to create the metrics, when set.
+	meterProvider metric.MeterProvider
+	// metrics records the decisions of the interceptors, when set.
+	metrics *interceptorMetrics
+}
+
+// WithHeaderName sets the metadata key holding the credentials, such as "x-api-key". Requests without this key are
+// rejected without invoking the authenticate function. The name is case-insensitive. When this option isn't used,
+// the incoming metadata is passed to the authenticate function whatever the keys it holds.
+func WithHeaderName(name string) InterceptorOption {
+	return func(opts *interceptorOptions) {
+		opts.headerName = strings.ToLower(name)
+	}
+}
+
+// WithMaxMetadataSize rejects requests whose credentials exceed the given size in bytes with codes.InvalidArgument,
+// before the authenticate function is invoked. The size is computed as the sum of the lengths of the values of the
+// credentials key, as set with WithHeaderName or "authorization" by default, other metadata not being accounted for.
+// A size of 0 or less disables the check, which is the default.
+func WithMaxMetadataSize(size int) InterceptorOption {
+	return func(opts *interceptorOptions) {
+		opts.maxMetadataSize = size
+	}
+}
+
+// WithExemptMethods skips the authentication for the given full method names, such as "/grpc.health.v1.Health/Check".
+// Requests to those methods are handled directly, even when they carry no metadata.
+func WithExemptMethods(methods ...string) InterceptorOption {
+	return func(opts *interceptorOptions) {
+		if opts.exemptMethods == nil {
+			opts.exemptMethods = make(map[string]struct{}, len(methods))
+		}
+		for _, m := range methods {
+			opts.exemptMethods[m] = struct{}{}
+		}
+	}
+}
+
+// WithExemptMethodPrefixes skips the authentication for the full method names starting with one of the given prefixes,
+// such as "/grpc.reflection.v1alpha.ServerReflection/". Requests to those methods are handled directly, even when they
+// carry no metadata.
+func WithExemptMethodPrefixes(prefixes ...string) InterceptorOption {
+	return func(opts *interceptorOptions) {
+		opts.exemptMethodPrefixes = append(opts.exemptMethodPrefixes, prefixes...)
+	}
+}
+
+// WithAuthorizeFunc sets a function invoked after a successful authentication, before the handler. When it returns an
+// error, the request is rejected with that error, like an authentication failure. A nil function is ignored.
+func WithAuthorizeFunc(authorize AuthorizeFunc) InterceptorOption {
+	return func(opts *interceptorOptions) {
+		opts.authorize = authorize
+	}
+}
+
+// WithMeterProvider records the outcome of each authentication decision as the auth_interceptor_successes and
+// auth_interceptor_failures counters of the given MeterProvider, labeled with the gRPC method. Failures are also
+// labeled with the reason of the rejection. Nothing is recorded when this option isn't used.
+func WithMeterProvider(mp metric.MeterProvider) InterceptorOption {
+	return func(opts *interceptorOptions) {
+		opts.meterProvider = mp
+	}
+}
+
+func newInterceptorOptions(opts []InterceptorOption) (*interceptorOptions, error) {
+	o := &interceptorOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.meterProvider != nil {
+		var err error
+		if o.metrics, err = newInterceptorMetrics(o.meterProvider); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+var defaultInterceptorOptions = &interceptorOptions{}
+
 // DefaultGRPCUnaryServerInterceptor provides a default implementation of GRPCUnaryInterceptorFunc, useful for most authenticators.
-// It extracts the headers from the incoming request, under the assumption that the credentials will be part of the resulting map.
-func DefaultGRPCUnaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler, authenticate AuthenticateFunc) (interface{}, error) {
-	headers, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, errMetadataNotFound
+// It extracts the headers from the incoming request, under the assumption that the credentials will be part of the resulting map
+// under the "authorization" key. The deadline of authenticated requests, if any, is recorded in their client.Info.
+func DefaultGRPCUnaryServerInterceptor(ctx context.Context, req interface{}, srvInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler, authenticate AuthenticateFunc) (interface{}, error) {
+	return defaultInterceptorOptions.unaryServerInterceptor(ctx, req, srvInfo, handler, authenticate)
+}
+
+// DefaultGRPCStreamServerInterceptor provides a default implementation of GRPCStreamInterceptorFunc, useful for most authenticators.
+// It extracts the headers from the incoming request, under the assumption that the credentials will be part of the resulting map
+// under the "authorization" key. The deadline of authenticated requests, if any, is recorded in their client.Info.
+func DefaultGRPCStreamServerInterceptor(srv interface{}, stream grpc.ServerStream, srvInfo *grpc.StreamServerInfo, handler grpc.StreamHandler, authenticate AuthenticateFunc) error {
+	return defaultInterceptorOptions.streamServerInterceptor(srv, stream, srvInfo, handler, authenticate)
+}
+
+// NewGRPCUnaryServerInterceptor returns a GRPCUnaryInterceptorFunc behaving like DefaultGRPCUnaryServerInterceptor,
+// customized by the given options. An error is returned when the metrics requested with WithMeterProvider can't be
+// created.
+func NewGRPCUnaryServerInterceptor(opts ...InterceptorOption) (GRPCUnaryInterceptorFunc, error) {
+	o, err := newInterceptorOptions(opts)
+	if err != nil {
+		return nil, err
 	}
+	return o.unaryServerInterceptor, nil
+}
 
-	ctx, err := authenticate(ctx, headers)
+// NewGRPCStreamServerInterceptor returns a GRPCStreamInterceptorFunc behaving like DefaultGRPCStreamServerInterceptor,
+// customized by the given options. An error is returned when the metrics requested with WithMeterProvider can't be
+// created.
+func NewGRPCStreamServerInterceptor(opts ...InterceptorOption) (GRPCStreamInterceptorFunc, error) {
+	o, err := newInterceptorOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return o.streamServerInterceptor, nil
+}
+
+func (o *interceptorOptions) unaryServerInterceptor(ctx context.Context, req interface{}, srvInfo *grpc.UnaryServerInfo, handler grpc.UnaryHandler, authenticate AuthenticateFunc) (interface{}, error) {
+	var fullMethod string
+	if srvInfo != nil {
+		fullMethod = srvInfo.FullMethod
+	}
+	if o.isExempt(fullMethod) {
+		return handler(ctx, req)
+	}
+
+	ctx, err := o.authenticate(ctx, fullMethod, authenticate)
 	if err != nil {
 		return nil, err
 	}
@@ -92,16 +238,16 @@ func DefaultGRPCUnaryServerInterceptor(ctx context.Context, req interface{}, _ *
 	return handler(ctx, req)
 }
 
-// DefaultGRPCStreamServerInterceptor provides a default implementation of GRPCStreamInterceptorFunc, useful for most authenticators.
-// It extracts the headers from the incoming request, under the assumption that the credentials will be part of the resulting map.
-func DefaultGRPCStreamServerInterceptor(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler, authenticate AuthenticateFunc) error {
-	ctx := stream.Context()
-	headers, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return errMetadataNotFound
+func (o *interceptorOptions) streamServerInterceptor(srv interface{}, stream grpc.ServerStream, srvInfo *grpc.StreamServerInfo, handler grpc.StreamHandler, authenticate AuthenticateFunc) error {
+	var fullMethod string
+	if srvInfo != nil {
+		fullMethod = srvInfo.FullMethod
+	}
+	if o.isExempt(fullMethod) {
+		return handler(srv, stream)
 	}
 
-	ctx, err := authenticate(ctx, headers)
+	ctx, err := o.authenticate(stream.Context(), fullMethod, authenticate)
 	if err != nil {
 		return err
 	}
@@ -110,3 +256,100 @@ func DefaultGRPCStreamServerInterceptor(srv interface{}, stream grpc.ServerStrea
 	wrapped.WrappedContext = ctx
 	return handler(srv, wrapped)
 }
+
+// authenticate performs the checks shared by the unary and stream interceptors, returning the context to be passed
+// down to the handler. Each decision is recorded in the interceptor metrics, when configured.
+func (o *interceptorOptions) authenticate(ctx context.Context, fullMethod string, authenticate AuthenticateFunc) (context.Context, error) {
+	headers, ok := metadata.FromIncomingContext(ctx)
+	if !ok || (o.headerName != "" && len(headers.Get(o.headerName)) == 0) {
+		o.metrics.recordFailure(ctx, fullMethod, reasonMissingMetadata)
+		return nil, errMetadataNotFound
+	}
+
+	if err := o.checkMetadataSize(headers); err != nil {
+		o.metrics.recordFailure(ctx, fullMethod, reasonMetadataTooLarge)
+		return nil, err
+	}
+
+	newCtx, err := authenticate(ctx, headers)
+	if err != nil {
+		o.metrics.recordFailure(ctx, fullMethod, reasonUnauthenticated)
+		return nil, err
+	}
+	newCtx = contextWithDeadline(newCtx)
+
+	if o.authorize != nil {
+		if err = o.authorize(newCtx); err != nil {
+			o.metrics.recordFailure(ctx, fullMethod, reasonUnauthorized)
+			return nil, err
+		}
+	}
+
+	o.metrics.recordSuccess(ctx, fullMethod)
+	return newCtx, nil
+}
+
+// contextWithDeadline records the deadline of the given context, if any, into its client.Info.
+func contextWithDeadline(ctx context.Context) context.Context {
+	cl := client.FromContext(ctx)
+	cl.Deadline, _ = ctx.Deadline()
+	return client.NewContext(ctx, cl)
+}
+
+// isExempt returns whether the authentication should be skipped for the given full method name.
+func (o *interceptorOptions) isExempt(fullMethod string) bool {
+	if _, ok := o.exemptMethods[fullMethod]; ok {
+		return true
+	}
+	for _, prefix := range o.exemptMethodPrefixes {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultHTTPServerHandler wraps the given http.Handler, authenticating each request with the given AuthenticateFunc
+// before invoking next. The request headers are passed to the AuthenticateFunc with lowercase keys, like the gRPC
+// metadata seen by DefaultGRPCUnaryServerInterceptor, and the resulting context is propagated to next.
+// Requests without headers or failing the authentication are rejected with a 401 status, without invoking next.
+func DefaultHTTPServerHandler(next http.Handler, authenticate AuthenticateFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.Header) == 0 {
+			http.Error(w, errMetadataNotFound.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		headers := make(map[string][]string, len(r.Header))
+		for k, v := range r.Header {
+			headers[strings.ToLower(k)] = v
+		}
+
+		ctx, err := authenticate(r.Context(), headers)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// checkMetadataSize returns an InvalidArgument error when the credentials metadata exceeds the configured maximum size.
+func (o *interceptorOptions) checkMetadataSize(headers metadata.MD) error {
+	if o.maxMetadataSize <= 0 {
+		return nil
+	}
+	key := o.headerName
+	if key == "" {
+		key = defaultHeaderName
+	}
+	size := 0
+	for _, v := range headers.Get(key) {
+		size += len(v)
+	}
+	if size > o.maxMetadataSize {
+		return status.Errorf(codes.InvalidArgument, "%q metadata size %d exceeds the maximum of %d bytes", key, size, o.maxMetadataSize)
+	}
+	return nil
+}
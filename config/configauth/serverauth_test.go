@@ -16,13 +16,21 @@ package configauth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/collector/client"
 )
@@ -177,6 +185,480 @@ func TestDefaultStreamInterceptorMissingMetadata(t *testing.T) {
 	assert.Equal(t, errMetadataNotFound, err)
 }
 
+func TestDefaultHTTPServerHandlerAuthSucceeded(t *testing.T) {
+	// prepare
+	handlerCalled := false
+	authCalled := false
+	authFunc := func(ctx context.Context, headers map[string][]string) (context.Context, error) {
+		authCalled = true
+		assert.Equal(t, []string{"some-auth-data"}, headers["authorization"])
+		return client.NewContext(ctx, client.Info{
+			Addr: &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+		}), nil
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+		// ensure that the client information is propagated down to the wrapped handler
+		cl := client.FromContext(r.Context())
+		assert.Equal(t, "1.2.3.4", cl.Addr.String())
+		w.WriteHeader(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", nil)
+	req.Header.Set("Authorization", "some-auth-data")
+	rec := httptest.NewRecorder()
+
+	// test
+	DefaultHTTPServerHandler(next, authFunc).ServeHTTP(rec, req)
+
+	// verify
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, authCalled)
+	assert.True(t, handlerCalled)
+}
+
+func TestDefaultHTTPServerHandlerAuthFailure(t *testing.T) {
+	// prepare
+	authCalled := false
+	authFunc := func(context.Context, map[string][]string) (context.Context, error) {
+		authCalled = true
+		return context.Background(), fmt.Errorf("not authenticated")
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.FailNow(t, "the handler should not have been called on auth failure!")
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", nil)
+	req.Header.Set("Authorization", "some-auth-data")
+	rec := httptest.NewRecorder()
+
+	// test
+	DefaultHTTPServerHandler(next, authFunc).ServeHTTP(rec, req)
+
+	// verify
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "not authenticated")
+	assert.True(t, authCalled)
+}
+
+func TestDefaultHTTPServerHandlerMissingHeaders(t *testing.T) {
+	// prepare
+	authFunc := func(context.Context, map[string][]string) (context.Context, error) {
+		assert.FailNow(t, "the auth func should not have been called!")
+		return context.Background(), nil
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.FailNow(t, "the handler should not have been called!")
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/traces", nil)
+	rec := httptest.NewRecorder()
+
+	// test
+	DefaultHTTPServerHandler(next, authFunc).ServeHTTP(rec, req)
+
+	// verify
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), errMetadataNotFound.Error())
+}
+
+func TestUnaryInterceptorHeaderName(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		opts        []InterceptorOption
+		md          metadata.MD
+		expectedErr error
+	}{
+		{
+			desc: "default header present",
+			md:   metadata.Pairs("authorization", "some-auth-data"),
+		},
+		{
+			desc: "no header required by default",
+			md:   metadata.Pairs("x-api-key", "some-auth-data"),
+		},
+		{
+			desc: "custom header present",
+			opts: []InterceptorOption{WithHeaderName("X-API-Key")},
+			md:   metadata.Pairs("x-api-key", "some-auth-data"),
+		},
+		{
+			desc:        "custom header missing",
+			opts:        []InterceptorOption{WithHeaderName("X-API-Key")},
+			md:          metadata.Pairs("authorization", "some-auth-data"),
+			expectedErr: errMetadataNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authCalled := false
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				authCalled = true
+				return ctx, nil
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), tC.md)
+			interceptor, err := NewGRPCUnaryServerInterceptor(tC.opts...)
+			require.NoError(t, err)
+
+			// test
+			_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler, authFunc)
+
+			// verify
+			assert.Equal(t, tC.expectedErr, err)
+			assert.Equal(t, tC.expectedErr == nil, authCalled)
+		})
+	}
+}
+
+func TestStreamInterceptorHeaderName(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		opts        []InterceptorOption
+		md          metadata.MD
+		expectedErr error
+	}{
+		{
+			desc: "custom header present",
+			opts: []InterceptorOption{WithHeaderName("x-api-key")},
+			md:   metadata.Pairs("x-api-key", "some-auth-data"),
+		},
+		{
+			desc:        "custom header missing",
+			opts:        []InterceptorOption{WithHeaderName("x-api-key")},
+			md:          metadata.Pairs("authorization", "some-auth-data"),
+			expectedErr: errMetadataNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authCalled := false
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				authCalled = true
+				return ctx, nil
+			}
+			handler := func(srv interface{}, stream grpc.ServerStream) error {
+				return nil
+			}
+			streamServer := &mockServerStream{
+				ctx: metadata.NewIncomingContext(context.Background(), tC.md),
+			}
+			interceptor, err := NewGRPCStreamServerInterceptor(tC.opts...)
+			require.NoError(t, err)
+
+			// test
+			err = interceptor(nil, streamServer, &grpc.StreamServerInfo{}, handler, authFunc)
+
+			// verify
+			assert.Equal(t, tC.expectedErr, err)
+			assert.Equal(t, tC.expectedErr == nil, authCalled)
+		})
+	}
+}
+
+func TestDefaultInterceptorsPassAnyMetadata(t *testing.T) {
+	// prepare
+	var unaryHeaders, streamHeaders map[string][]string
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "some-auth-data"))
+
+	// test
+	_, unaryErr := DefaultGRPCUnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		},
+		func(ctx context.Context, headers map[string][]string) (context.Context, error) {
+			unaryHeaders = headers
+			return ctx, nil
+		})
+	streamErr := DefaultGRPCStreamServerInterceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		},
+		func(ctx context.Context, headers map[string][]string) (context.Context, error) {
+			streamHeaders = headers
+			return ctx, nil
+		})
+
+	// verify
+	assert.NoError(t, unaryErr)
+	assert.NoError(t, streamErr)
+	assert.Equal(t, []string{"some-auth-data"}, unaryHeaders["x-api-key"])
+	assert.Equal(t, []string{"some-auth-data"}, streamHeaders["x-api-key"])
+}
+
+func TestUnaryInterceptorPropagatesAuthData(t *testing.T) {
+	// prepare
+	authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+		cl := client.FromContext(ctx)
+		cl.Auth = client.NewAuthData(map[string]interface{}{
+			"subject": "jdoe",
+			"tenant":  "acme",
+		})
+		return client.NewContext(ctx, cl), nil
+	}
+	// the handler acts as the next component in the pipeline, such as a processor reading the claims
+	var subject, tenant interface{}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		cl := client.FromContext(ctx)
+		subject = cl.Auth.GetAttribute("subject")
+		tenant = cl.Auth.GetAttribute("tenant")
+		return nil, nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data"))
+
+	// test
+	_, err := DefaultGRPCUnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler, authFunc)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe", subject)
+	assert.Equal(t, "acme", tenant)
+}
+
+func TestStreamInterceptorPropagatesAuthData(t *testing.T) {
+	// prepare
+	authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+		cl := client.FromContext(ctx)
+		cl.Auth = client.NewAuthData(map[string]interface{}{
+			"subject": "jdoe",
+		})
+		return client.NewContext(ctx, cl), nil
+	}
+	var subject interface{}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		subject = client.FromContext(stream.Context()).Auth.GetAttribute("subject")
+		return nil
+	}
+	streamServer := &mockServerStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data")),
+	}
+
+	// test
+	err := DefaultGRPCStreamServerInterceptor(nil, streamServer, &grpc.StreamServerInfo{}, handler, authFunc)
+
+	// verify
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe", subject)
+}
+
+func TestUnaryInterceptorExemptMethods(t *testing.T) {
+	opts := []InterceptorOption{
+		WithExemptMethods("/grpc.health.v1.Health/Check"),
+		WithExemptMethodPrefixes("/grpc.reflection.v1alpha.ServerReflection/"),
+	}
+	testCases := []struct {
+		desc        string
+		method      string
+		expectedErr error
+	}{
+		{
+			desc:   "exact match",
+			method: "/grpc.health.v1.Health/Check",
+		},
+		{
+			desc:   "prefix match",
+			method: "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+		},
+		{
+			desc:        "not exempt",
+			method:      "/opentelemetry.proto.collector.trace.v1.TraceService/Export",
+			expectedErr: errMetadataNotFound,
+		},
+		{
+			desc:        "exact match only",
+			method:      "/grpc.health.v1.Health/Watch",
+			expectedErr: errMetadataNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authFunc := func(context.Context, map[string][]string) (context.Context, error) {
+				assert.FailNow(t, "the auth func should not have been called!")
+				return context.Background(), nil
+			}
+			handlerCalled := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCalled = true
+				return nil, nil
+			}
+			interceptor, err := NewGRPCUnaryServerInterceptor(opts...)
+			require.NoError(t, err)
+
+			// test, without any metadata in the context
+			_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tC.method}, handler, authFunc)
+
+			// verify
+			assert.Equal(t, tC.expectedErr, err)
+			assert.Equal(t, tC.expectedErr == nil, handlerCalled)
+		})
+	}
+}
+
+func TestStreamInterceptorExemptMethods(t *testing.T) {
+	opts := []InterceptorOption{
+		WithExemptMethods("/grpc.health.v1.Health/Watch"),
+	}
+	testCases := []struct {
+		desc        string
+		method      string
+		expectedErr error
+	}{
+		{
+			desc:   "exempt",
+			method: "/grpc.health.v1.Health/Watch",
+		},
+		{
+			desc:        "not exempt",
+			method:      "/opentelemetry.proto.collector.trace.v1.TraceService/Export",
+			expectedErr: errMetadataNotFound,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authFunc := func(context.Context, map[string][]string) (context.Context, error) {
+				assert.FailNow(t, "the auth func should not have been called!")
+				return context.Background(), nil
+			}
+			handlerCalled := false
+			handler := func(srv interface{}, stream grpc.ServerStream) error {
+				handlerCalled = true
+				return nil
+			}
+			streamServer := &mockServerStream{
+				ctx: context.Background(),
+			}
+			interceptor, err := NewGRPCStreamServerInterceptor(opts...)
+			require.NoError(t, err)
+
+			// test
+			err = interceptor(nil, streamServer, &grpc.StreamServerInfo{FullMethod: tC.method}, handler, authFunc)
+
+			// verify
+			assert.Equal(t, tC.expectedErr, err)
+			assert.Equal(t, tC.expectedErr == nil, handlerCalled)
+		})
+	}
+}
+
+func requireScope(scope string) AuthorizeFunc {
+	return func(ctx context.Context) error {
+		auth := client.FromContext(ctx).Auth
+		if auth == nil {
+			return errors.New("no auth data")
+		}
+		scopes, _ := auth.GetAttribute("scopes").([]string)
+		for _, s := range scopes {
+			if s == scope {
+				return nil
+			}
+		}
+		return fmt.Errorf("missing scope %q", scope)
+	}
+}
+
+func TestUnaryInterceptorAuthorize(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		authorize   AuthorizeFunc
+		scopes      []string
+		expectedErr error
+	}{
+		{
+			desc:      "authorized",
+			authorize: requireScope("metrics:write"),
+			scopes:    []string{"metrics:write"},
+		},
+		{
+			desc:        "unauthorized",
+			authorize:   requireScope("metrics:write"),
+			scopes:      []string{"traces:write"},
+			expectedErr: fmt.Errorf("missing scope %q", "metrics:write"),
+		},
+		{
+			desc:   "no authorize func",
+			scopes: []string{"traces:write"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				cl := client.FromContext(ctx)
+				cl.Auth = client.NewAuthData(map[string]interface{}{"scopes": tC.scopes})
+				return client.NewContext(ctx, cl), nil
+			}
+			handlerCalled := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handlerCalled = true
+				return nil, nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data"))
+			interceptor, err := NewGRPCUnaryServerInterceptor(WithAuthorizeFunc(tC.authorize))
+			require.NoError(t, err)
+
+			// test
+			_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler, authFunc)
+
+			// verify
+			assert.Equal(t, tC.expectedErr, err)
+			assert.Equal(t, tC.expectedErr == nil, handlerCalled)
+		})
+	}
+}
+
+func TestStreamInterceptorAuthorize(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		authorize   AuthorizeFunc
+		scopes      []string
+		expectedErr error
+	}{
+		{
+			desc:      "authorized",
+			authorize: requireScope("metrics:write"),
+			scopes:    []string{"metrics:write"},
+		},
+		{
+			desc:        "unauthorized",
+			authorize:   requireScope("metrics:write"),
+			expectedErr: fmt.Errorf("missing scope %q", "metrics:write"),
+		},
+		{
+			desc: "no authorize func",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				cl := client.FromContext(ctx)
+				cl.Auth = client.NewAuthData(map[string]interface{}{"scopes": tC.scopes})
+				return client.NewContext(ctx, cl), nil
+			}
+			handlerCalled := false
+			handler := func(srv interface{}, stream grpc.ServerStream) error {
+				handlerCalled = true
+				return nil
+			}
+			streamServer := &mockServerStream{
+				ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data")),
+			}
+			interceptor, err := NewGRPCStreamServerInterceptor(WithAuthorizeFunc(tC.authorize))
+			require.NoError(t, err)
+
+			// test
+			err = interceptor(nil, streamServer, &grpc.StreamServerInfo{}, handler, authFunc)
+
+			// verify
+			assert.Equal(t, tC.expectedErr, err)
+			assert.Equal(t, tC.expectedErr == nil, handlerCalled)
+		})
+	}
+}
+
 type mockServerStream struct {
 	grpc.ServerStream
 	ctx context.Context
@@ -185,3 +667,208 @@ type mockServerStream struct {
 func (m *mockServerStream) Context() context.Context {
 	return m.ctx
 }
+
+func TestUnaryInterceptorMaxMetadataSize(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		opts    []InterceptorOption
+		md      metadata.MD
+		allowed bool
+	}{
+		{
+			desc:    "within limit",
+			md:      metadata.Pairs("authorization", "some-auth-data"),
+			allowed: true,
+		},
+		{
+			desc:    "exceeds limit",
+			md:      metadata.Pairs("authorization", strings.Repeat("a", 100)),
+			allowed: false,
+		},
+		{
+			desc:    "large non-credentials metadata",
+			md:      metadata.Pairs("authorization", "some-auth-data", "user-agent", strings.Repeat("a", 100)),
+			allowed: true,
+		},
+		{
+			desc:    "custom header exceeds limit",
+			opts:    []InterceptorOption{WithHeaderName("x-api-key")},
+			md:      metadata.Pairs("x-api-key", strings.Repeat("a", 100)),
+			allowed: false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authCalled := false
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				authCalled = true
+				return ctx, nil
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), tC.md)
+			interceptor, err := NewGRPCUnaryServerInterceptor(append(tC.opts, WithMaxMetadataSize(64))...)
+			require.NoError(t, err)
+
+			// test
+			_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler, authFunc)
+
+			// verify
+			assert.Equal(t, tC.allowed, authCalled)
+			if tC.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestStreamInterceptorMaxMetadataSize(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		opts    []InterceptorOption
+		md      metadata.MD
+		allowed bool
+	}{
+		{
+			desc:    "within limit",
+			md:      metadata.Pairs("authorization", "some-auth-data"),
+			allowed: true,
+		},
+		{
+			desc:    "exceeds limit",
+			md:      metadata.Pairs("authorization", strings.Repeat("a", 100)),
+			allowed: false,
+		},
+		{
+			desc:    "large non-credentials metadata",
+			md:      metadata.Pairs("authorization", "some-auth-data", "user-agent", strings.Repeat("a", 100)),
+			allowed: true,
+		},
+		{
+			desc:    "custom header exceeds limit",
+			opts:    []InterceptorOption{WithHeaderName("x-api-key")},
+			md:      metadata.Pairs("x-api-key", strings.Repeat("a", 100)),
+			allowed: false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authCalled := false
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				authCalled = true
+				return ctx, nil
+			}
+			handler := func(srv interface{}, stream grpc.ServerStream) error {
+				return nil
+			}
+			streamServer := &mockServerStream{
+				ctx: metadata.NewIncomingContext(context.Background(), tC.md),
+			}
+			interceptor, err := NewGRPCStreamServerInterceptor(append(tC.opts, WithMaxMetadataSize(64))...)
+			require.NoError(t, err)
+
+			// test
+			err = interceptor(nil, streamServer, &grpc.StreamServerInfo{}, handler, authFunc)
+
+			// verify
+			assert.Equal(t, tC.allowed, authCalled)
+			if tC.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestUnaryInterceptorRecordsDeadline(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	testCases := []struct {
+		desc     string
+		deadline time.Time
+	}{
+		{
+			desc:     "with deadline",
+			deadline: deadline,
+		},
+		{
+			desc: "without deadline",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				return ctx, nil
+			}
+			var recorded time.Time
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				recorded = client.FromContext(ctx).Deadline
+				return nil, nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data"))
+			if !tC.deadline.IsZero() {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, tC.deadline)
+				defer cancel()
+			}
+
+			// test
+			_, err := DefaultGRPCUnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler, authFunc)
+
+			// verify
+			assert.NoError(t, err)
+			assert.True(t, tC.deadline.Equal(recorded))
+			assert.Equal(t, tC.deadline.IsZero(), recorded.IsZero())
+		})
+	}
+}
+
+func TestStreamInterceptorRecordsDeadline(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	testCases := []struct {
+		desc     string
+		deadline time.Time
+	}{
+		{
+			desc:     "with deadline",
+			deadline: deadline,
+		},
+		{
+			desc: "without deadline",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			authFunc := func(ctx context.Context, _ map[string][]string) (context.Context, error) {
+				// authenticators replacing the client.Info don't discard the deadline
+				return client.NewContext(ctx, client.Info{}), nil
+			}
+			var recorded time.Time
+			handler := func(srv interface{}, stream grpc.ServerStream) error {
+				recorded = client.FromContext(stream.Context()).Deadline
+				return nil
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "some-auth-data"))
+			if !tC.deadline.IsZero() {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, tC.deadline)
+				defer cancel()
+			}
+
+			// test
+			err := DefaultGRPCStreamServerInterceptor(nil, &mockServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, handler, authFunc)
+
+			// verify
+			assert.NoError(t, err)
+			assert.True(t, tC.deadline.Equal(recorded))
+			assert.Equal(t, tC.deadline.IsZero(), recorded.IsZero())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc // import "go.opentelemetry.io/collector/config/configgrpc"
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/internal/middleware"
+)
+
+type connCacheKey struct{}
+
+// connClientInfo holds the client.Info resolved for a single connection.
+type connClientInfo struct {
+	once sync.Once
+	info client.Info
+}
+
+var _ stats.Handler = (*connCacheStatsHandler)(nil)
+
+// connCacheStatsHandler is a stats.Handler placing an empty connClientInfo in the context of each new connection.
+// The context of the requests received on a connection derives from the connection's context, allowing the
+// interceptors to share data across the requests of a connection.
+type connCacheStatsHandler struct{}
+
+func (h *connCacheStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connCacheKey{}, &connClientInfo{})
+}
+
+func (h *connCacheStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+func (h *connCacheStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *connCacheStatsHandler) HandleRPC(context.Context, stats.RPCStats) {}
+
+// connClientInfoResolver intercepts the incoming RPCs, enriching their client.Info with the data resolved once
+// for the connection they were received on.
+type connClientInfoResolver struct {
+	resolve func(p *peer.Peer) client.Info
+	logger  *zap.Logger
+	// warnOnce ensures the missing connection cache is reported once.
+	warnOnce sync.Once
+}
+
+func (r *connClientInfoResolver) unaryServerInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(r.contextWithResolvedClient(ctx), req)
+}
+
+func (r *connClientInfoResolver) streamServerInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	wrapped := middleware.WrapServerStream(ss)
+	wrapped.WrappedContext = r.contextWithResolvedClient(ss.Context())
+	return handler(srv, wrapped)
+}
+
+// contextWithResolvedClient fills the missing fields of the client.Info from the context with the data resolved for
+// the connection. The resolution happens at most once per connection, unless the context isn't tied to one, such as
+// when the connCacheStatsHandler was replaced by another grpc.StatsHandler.
+func (r *connClientInfoResolver) contextWithResolvedClient(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	var resolved client.Info
+	if cache, ok := ctx.Value(connCacheKey{}).(*connClientInfo); ok {
+		cache.once.Do(func() {
+			cache.info = r.resolve(p)
+		})
+		resolved = cache.info
+	} else {
+		r.warnOnce.Do(func() {
+			if r.logger != nil {
+				r.logger.Warn("Client info is resolved for each request instead of once per connection: " +
+					"the connection cache was likely replaced by another grpc.StatsHandler server option")
+			}
+		})
+		resolved = r.resolve(p)
+	}
+
+	cl := client.FromContext(ctx)
+	if cl.Addr == nil {
+		cl.Addr = resolved.Addr
+	}
+	if cl.Auth == nil {
+		cl.Auth = resolved.Auth
+	}
+	return client.NewContext(ctx, cl)
+}
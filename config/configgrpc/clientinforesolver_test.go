@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configgrpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+)
+
+// countingResolver returns a client info resolver counting its calls, and setting the peer's address as the subject.
+func countingResolver(calls *int32) func(p *peer.Peer) client.Info {
+	return func(p *peer.Peer) client.Info {
+		atomic.AddInt32(calls, 1)
+		return client.Info{
+			Auth: client.NewAuthData(map[string]interface{}{"subject": p.Addr.String()}),
+		}
+	}
+}
+
+func TestClientInfoResolverOncePerConnection(t *testing.T) {
+	// prepare
+	var calls int32
+	mock := &grpcTraceServer{}
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  "localhost:0",
+			Transport: "tcp",
+		},
+		ClientInfoResolver: countingResolver(&calls),
+	}
+	opts, err := gss.ToServerOption(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	srv := grpc.NewServer(opts...)
+	otlpgrpc.RegisterTracesServer(srv, mock)
+	defer srv.Stop()
+
+	l, err := gss.ToListener()
+	require.NoError(t, err)
+	go func() {
+		_ = srv.Serve(l)
+	}()
+
+	gcs := &GRPCClientSettings{
+		Endpoint: l.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	clientOpts, err := gcs.ToDialOptions(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFunc()
+
+	// test
+	for conn := 1; conn <= 2; conn++ {
+		grpcClientConn, errDial := grpc.Dial(gcs.Endpoint, clientOpts...)
+		require.NoError(t, errDial)
+
+		cl := otlpgrpc.NewTracesClient(grpcClientConn)
+		for i := 0; i < 3; i++ {
+			_, errResp := cl.Export(ctx, otlpgrpc.NewTracesRequest())
+			require.NoError(t, errResp)
+
+			// verify
+			info := client.FromContext(mock.recordedContext)
+			require.NotNil(t, info.Auth)
+			assert.Equal(t, info.Addr.String(), info.Auth.GetAttribute("subject"))
+		}
+		require.NoError(t, grpcClientConn.Close())
+
+		// verify
+		assert.EqualValues(t, conn, atomic.LoadInt32(&calls))
+	}
+}
+
+func TestClientInfoResolverWithAnotherStatsHandler(t *testing.T) {
+	// prepare
+	var calls int32
+	core, logs := observer.New(zapcore.WarnLevel)
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.Logger = zap.New(core)
+	mock := &grpcTraceServer{}
+	gss := &GRPCServerSettings{
+		NetAddr: confignet.NetAddr{
+			Endpoint:  "localhost:0",
+			Transport: "tcp",
+		},
+		ClientInfoResolver: countingResolver(&calls),
+	}
+	opts, err := gss.ToServerOption(componenttest.NewNopHost(), settings)
+	require.NoError(t, err)
+	// a stats handler passed after the returned options replaces the connection cache
+	srv := grpc.NewServer(append(opts, grpc.StatsHandler(&nopStatsHandler{}))...)
+	otlpgrpc.RegisterTracesServer(srv, mock)
+	defer srv.Stop()
+
+	l, err := gss.ToListener()
+	require.NoError(t, err)
+	go func() {
+		_ = srv.Serve(l)
+	}()
+
+	gcs := &GRPCClientSettings{
+		Endpoint: l.Addr().String(),
+		TLSSetting: configtls.TLSClientSetting{
+			Insecure: true,
+		},
+	}
+	clientOpts, err := gcs.ToDialOptions(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	grpcClientConn, err := grpc.Dial(gcs.Endpoint, clientOpts...)
+	require.NoError(t, err)
+	defer grpcClientConn.Close()
+	cl := otlpgrpc.NewTracesClient(grpcClientConn)
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelFunc()
+
+	// test
+	for i := 0; i < 3; i++ {
+		_, err = cl.Export(ctx, otlpgrpc.NewTracesRequest())
+		require.NoError(t, err)
+		assert.NotNil(t, client.FromContext(mock.recordedContext).Auth)
+	}
+
+	// verify: the resolver is still invoked, once per request, and the fallback is reported once
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+	assert.Equal(t, 1, logs.Len())
+}
+
+// nopStatsHandler is a stats.Handler doing nothing.
+type nopStatsHandler struct{}
+
+func (h *nopStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *nopStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+func (h *nopStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *nopStatsHandler) HandleRPC(context.Context, stats.RPCStats) {}
+
+func TestClientInfoResolverPrecedence(t *testing.T) {
+	// prepare
+	var calls int32
+	resolver := &connClientInfoResolver{resolve: countingResolver(&calls)}
+	addr := &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)}
+	existing := client.NewAuthData(map[string]interface{}{"subject": "authenticator"})
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+	ctx = client.NewContext(ctx, client.Info{Auth: existing})
+
+	// test
+	ctx = resolver.contextWithResolvedClient(ctx)
+
+	// verify
+	assert.Equal(t, existing, client.FromContext(ctx).Auth)
+	assert.EqualValues(t, 1, calls)
+}
+
+func TestClientInfoResolverWithoutConnectionCache(t *testing.T) {
+	// prepare
+	var calls int32
+	resolver := &connClientInfoResolver{resolve: countingResolver(&calls)}
+	addr := &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+	// test
+	for i := 0; i < 2; i++ {
+		cl := client.FromContext(resolver.contextWithResolvedClient(ctx))
+		require.NotNil(t, cl.Auth)
+		assert.Equal(t, "1.2.3.4", cl.Auth.GetAttribute("subject"))
+	}
+
+	// verify: without the connection's cache, each request is resolved
+	assert.EqualValues(t, 2, calls)
+
+	// a context without a peer is left untouched
+	assert.Equal(t, context.Background(), resolver.contextWithResolvedClient(context.Background()))
+}
+
+func TestClientInfoResolverSharedAcrossConnectionRequests(t *testing.T) {
+	// prepare
+	var calls int32
+	resolver := &connClientInfoResolver{resolve: countingResolver(&calls)}
+	handler := &connCacheStatsHandler{}
+	connCtx := handler.TagConn(context.Background(), nil)
+
+	// test
+	for i := 0; i < 3; i++ {
+		rpcCtx := peer.NewContext(handler.TagRPC(connCtx, nil), &peer.Peer{Addr: &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)}})
+		stream := &mockedStream{ctx: rpcCtx}
+		err := resolver.streamServerInterceptor(nil, stream, nil, func(srv interface{}, stream grpc.ServerStream) error {
+			assert.NotNil(t, client.FromContext(stream.Context()).Auth)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	// verify
+	assert.EqualValues(t, 1, calls)
+}
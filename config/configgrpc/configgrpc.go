@@ -164,6 +164,17 @@ type GRPCServerSettings struct {
 
 	// Auth for this receiver
 	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
+
+	// ClientInfoResolver, when set, resolves the client.Info for the connection requests are received on, such as
+	// authentication data derived from the peer's TLS certificate. It is invoked once per connection, the result
+	// being reused for all the requests received on the same connection. Data already present in the request's
+	// client.Info, such as the one set by authenticators, takes precedence over the resolved data.
+	// The per-connection cache relies on a grpc.StatsHandler added to the options returned by ToServerOption. As a gRPC
+	// server keeps a single stats handler, setting this field conflicts with any other grpc.StatsHandler option: when
+	// another one is passed after the returned options, the resolver is invoked for each request instead; when it is
+	// passed before, it is replaced and doesn't receive any event. The former case is reported by a warning logged on
+	// the first request not tied to the cache.
+	ClientInfoResolver func(p *peer.Peer) client.Info
 }
 
 // SanitizedEndpoint strips the prefix of either http:// or https:// from configgrpc.GRPCClientSettings.Endpoint.
@@ -359,6 +370,13 @@ func (gss *GRPCServerSettings) ToServerOption(host component.Host, settings comp
 	uInterceptors = append(uInterceptors, enhanceWithClientInformation)
 	sInterceptors = append(sInterceptors, enhanceStreamWithClientInformation)
 
+	if gss.ClientInfoResolver != nil {
+		resolver := &connClientInfoResolver{resolve: gss.ClientInfoResolver, logger: settings.Logger}
+		opts = append(opts, grpc.StatsHandler(&connCacheStatsHandler{}))
+		uInterceptors = append(uInterceptors, resolver.unaryServerInterceptor)
+		sInterceptors = append(sInterceptors, resolver.streamServerInterceptor)
+	}
+
 	opts = append(opts, grpc.ChainUnaryInterceptor(uInterceptors...), grpc.ChainStreamInterceptor(sInterceptors...))
 
 	return opts, nil
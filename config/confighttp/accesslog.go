@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	accessLogFieldMethod     = "method"
+	accessLogFieldPath       = "path"
+	accessLogFieldStatus     = "status"
+	accessLogFieldDuration   = "duration"
+	accessLogFieldClientAddr = "client_addr"
+)
+
+var accessLogFields = []string{
+	accessLogFieldMethod,
+	accessLogFieldPath,
+	accessLogFieldStatus,
+	accessLogFieldDuration,
+	accessLogFieldClientAddr,
+}
+
+// AccessLogSettings configures the access logs emitted by an HTTP server.
+type AccessLogSettings struct {
+	// Enabled turns on access logging for every request handled by the server.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Fields lists the fields to include in each log entry. Valid values are "method", "path",
+	// "status", "duration" and "client_addr". If empty, all fields are included.
+	Fields []string `mapstructure:"fields,omitempty"`
+
+	// SamplingRate is the fraction of requests that are logged, between 0 and 1. Requests are
+	// sampled deterministically, e.g. a rate of 0.25 logs one out of every four requests, and
+	// a rate of 0 logs none of them. If not set, all requests are logged.
+	SamplingRate *float64 `mapstructure:"sampling_rate,omitempty"`
+}
+
+// Validate checks that the access log settings are valid.
+func (als *AccessLogSettings) Validate() error {
+	for _, f := range als.Fields {
+		if !isAccessLogField(f) {
+			return fmt.Errorf("unsupported access log field %q, valid fields are %q", f, accessLogFields)
+		}
+	}
+	if als.SamplingRate != nil && (*als.SamplingRate < 0 || *als.SamplingRate > 1) {
+		return fmt.Errorf("access log sampling_rate must be between 0 and 1, got %v", *als.SamplingRate)
+	}
+	return nil
+}
+
+func isAccessLogField(f string) bool {
+	for _, valid := range accessLogFields {
+		if f == valid {
+			return true
+		}
+	}
+	return false
+}
+
+var _ http.Handler = (*accessLogHandler)(nil)
+
+// accessLogHandler is an http.Handler that logs a structured entry for a sample of the handled requests.
+type accessLogHandler struct {
+	next   http.Handler
+	logger *zap.Logger
+	fields map[string]bool
+	rate   float64
+	// count is the number of requests seen so far, used for sampling.
+	count uint64
+}
+
+func newAccessLogHandler(next http.Handler, logger *zap.Logger, als *AccessLogSettings) *accessLogHandler {
+	fields := als.Fields
+	if len(fields) == 0 {
+		fields = accessLogFields
+	}
+	h := &accessLogHandler{
+		next:   next,
+		logger: logger,
+		fields: make(map[string]bool, len(fields)),
+		rate:   1,
+	}
+	for _, f := range fields {
+		h.fields[f] = true
+	}
+	if als.SamplingRate != nil {
+		h.rate = *als.SamplingRate
+	}
+	return h
+}
+
+// ServeHTTP serves the request with the next handler, logging it afterwards when sampled.
+func (h *accessLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !h.sampled() {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+
+	start := time.Now()
+	sw := &statusRecordingWriter{ResponseWriter: w, status: http.StatusOK}
+	h.next.ServeHTTP(sw, req)
+
+	var fields []zap.Field
+	if h.fields[accessLogFieldMethod] {
+		fields = append(fields, zap.String(accessLogFieldMethod, req.Method))
+	}
+	if h.fields[accessLogFieldPath] {
+		fields = append(fields, zap.String(accessLogFieldPath, req.URL.Path))
+	}
+	if h.fields[accessLogFieldStatus] {
+		fields = append(fields, zap.Int(accessLogFieldStatus, sw.status))
+	}
+	if h.fields[accessLogFieldDuration] {
+		fields = append(fields, zap.Duration(accessLogFieldDuration, time.Since(start)))
+	}
+	if h.fields[accessLogFieldClientAddr] {
+		fields = append(fields, zap.String(accessLogFieldClientAddr, req.RemoteAddr))
+	}
+	h.logger.Info("HTTP request", fields...)
+}
+
+// sampled reports whether the current request should be logged. A request is logged whenever the
+// expected number of logged requests, count*rate, crosses the next integer.
+func (h *accessLogHandler) sampled() bool {
+	n := atomic.AddUint64(&h.count, 1)
+	return math.Floor(float64(n)*h.rate) != math.Floor(float64(n-1)*h.rate)
+}
+
+// statusRecordingWriter is an http.ResponseWriter that records the status code of the response.
+type statusRecordingWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecordingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
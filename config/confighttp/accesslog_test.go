@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestAccessLogHandlerFields(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		fields   []string
+		expected []string
+	}{
+		{
+			desc:     "all fields by default",
+			expected: accessLogFields,
+		},
+		{
+			desc:     "selected fields",
+			fields:   []string{"method", "status"},
+			expected: []string{"method", "status"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+			})
+			h := newAccessLogHandler(next, zap.New(core), &AccessLogSettings{Enabled: true, Fields: tC.fields})
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/traces", nil)
+			req.RemoteAddr = "1.2.3.4:55443"
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusAccepted, rec.Code)
+			require.Equal(t, 1, logs.Len())
+			entry := logs.All()[0].ContextMap()
+			assert.Len(t, entry, len(tC.expected))
+			for _, f := range tC.expected {
+				assert.Contains(t, entry, f)
+			}
+			if _, ok := entry["method"]; ok {
+				assert.Equal(t, http.MethodPost, entry["method"])
+			}
+			if _, ok := entry["path"]; ok {
+				assert.Equal(t, "/v1/traces", entry["path"])
+			}
+			if _, ok := entry["status"]; ok {
+				assert.EqualValues(t, http.StatusAccepted, entry["status"])
+			}
+			if _, ok := entry["client_addr"]; ok {
+				assert.Equal(t, "1.2.3.4:55443", entry["client_addr"])
+			}
+		})
+	}
+}
+
+func TestAccessLogHandlerSampling(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rate     *float64
+		expected int
+	}{
+		{
+			desc:     "default rate logs everything",
+			expected: 100,
+		},
+		{
+			desc:     "everything",
+			rate:     samplingRate(1),
+			expected: 100,
+		},
+		{
+			desc:     "half",
+			rate:     samplingRate(0.5),
+			expected: 50,
+		},
+		{
+			desc:     "one in ten",
+			rate:     samplingRate(0.1),
+			expected: 10,
+		},
+		{
+			desc:     "zero rate logs nothing",
+			rate:     samplingRate(0),
+			expected: 0,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+			handled := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handled++
+			})
+			h := newAccessLogHandler(next, zap.New(core), &AccessLogSettings{Enabled: true, SamplingRate: tC.rate})
+
+			for i := 0; i < 100; i++ {
+				h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			}
+
+			assert.Equal(t, 100, handled)
+			assert.Equal(t, tC.expected, logs.Len())
+		})
+	}
+}
+
+func TestAccessLogSettingsValidate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		settings AccessLogSettings
+		err      string
+	}{
+		{
+			desc:     "valid",
+			settings: AccessLogSettings{Fields: []string{"path"}, SamplingRate: samplingRate(0.5)},
+		},
+		{
+			desc:     "zero sampling rate",
+			settings: AccessLogSettings{SamplingRate: samplingRate(0)},
+		},
+		{
+			desc:     "unknown field",
+			settings: AccessLogSettings{Fields: []string{"user_agent"}},
+			err:      `unsupported access log field "user_agent"`,
+		},
+		{
+			desc:     "sampling rate too high",
+			settings: AccessLogSettings{SamplingRate: samplingRate(2)},
+			err:      "access log sampling_rate must be between 0 and 1",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := tC.settings.Validate()
+			if tC.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tC.err)
+			}
+		})
+	}
+}
+
+func samplingRate(rate float64) *float64 {
+	return &rate
+}
+
+func TestToServerAccessLog(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.Logger = zap.New(core)
+
+	hss := HTTPServerSettings{
+		AccessLog: &AccessLogSettings{Enabled: true},
+	}
+	srv, err := hss.ToServer(componenttest.NewNopHost(), settings, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	require.NoError(t, err)
+
+	srv.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, 1, logs.Len())
+
+	hss.AccessLog.Fields = []string{"invalid"}
+	_, err = hss.ToServer(componenttest.NewNopHost(), settings, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	assert.Error(t, err)
+}
@@ -15,10 +15,12 @@
 package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/rs/cors"
@@ -56,6 +58,11 @@ type HTTPClientSettings struct {
 	// Custom Round Tripper to allow for individual components to intercept HTTP requests
 	CustomRoundTripper func(next http.RoundTripper) (http.RoundTripper, error)
 
+	// ResponseValidator, when set, is invoked once the body of each response has been fully read, at which
+	// point the response trailers are available in http.Response.Trailer. Returning an error makes the read of
+	// the body fail with that error, allowing components to act on statuses reported in trailers.
+	ResponseValidator func(resp *http.Response) error
+
 	// Auth configuration for outgoing HTTP calls.
 	Auth *configauth.Authentication `mapstructure:"auth,omitempty"`
 
@@ -75,6 +82,16 @@ type HTTPClientSettings struct {
 	// IdleConnTimeout is the maximum amount of time a connection will remain open before closing itself.
 	// There's an already set value, and we want to override it only if an explicit value provided
 	IdleConnTimeout *time.Duration `mapstructure:"idle_conn_timeout"`
+
+	// ALPNDiagnostics enables more detailed errors when the TLS handshake fails because the client and the
+	// server could not agree on an application protocol (ALPN), including the protocols offered by the client.
+	ALPNDiagnostics bool `mapstructure:"alpn_diagnostics"`
+
+	// FollowRedirects controls whether the client follows HTTP redirects. When false, the redirect response is
+	// returned to the caller. When true, redirects are followed but sensitive headers, including the configured
+	// Headers, are removed when redirecting to a different host.
+	// If not set, the default behavior of the Go HTTP client is kept.
+	FollowRedirects *bool `mapstructure:"follow_redirects"`
 }
 
 // DefaultHTTPClientSettings returns HTTPClientSettings type object with
@@ -126,9 +143,19 @@ func (hcs *HTTPClientSettings) ToClient(ext map[config.ComponentID]component.Ext
 	}
 
 	clientTransport := (http.RoundTripper)(transport)
+	if hcs.ALPNDiagnostics {
+		clientTransport = &alpnRoundTripper{
+			transport: transport,
+		}
+	}
+
+	if hcs.FollowRedirects != nil && *hcs.FollowRedirects {
+		clientTransport = newRedirectHeaderStripper(clientTransport, hcs.Headers)
+	}
+
 	if len(hcs.Headers) > 0 {
 		clientTransport = &headerRoundTripper{
-			transport: transport,
+			transport: clientTransport,
 			headers:   hcs.Headers,
 		}
 	}
@@ -156,10 +183,23 @@ func (hcs *HTTPClientSettings) ToClient(ext map[config.ComponentID]component.Ext
 		}
 	}
 
-	return &http.Client{
+	if hcs.ResponseValidator != nil {
+		clientTransport = &trailerRoundTripper{
+			transport: clientTransport,
+			validator: hcs.ResponseValidator,
+		}
+	}
+
+	httpClient := &http.Client{
 		Transport: clientTransport,
 		Timeout:   hcs.Timeout,
-	}, nil
+	}
+	if hcs.FollowRedirects != nil && !*hcs.FollowRedirects {
+		httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return httpClient, nil
 }
 
 // Custom RoundTripper that adds headers.
@@ -177,6 +217,34 @@ func (interceptor *headerRoundTripper) RoundTrip(req *http.Request) (*http.Respo
 	return interceptor.transport.RoundTrip(req)
 }
 
+// Custom RoundTripper that explains TLS handshake failures caused by an ALPN mismatch.
+type alpnRoundTripper struct {
+	transport *http.Transport
+}
+
+// RoundTrip sends the request and, when the TLS handshake fails due to an ALPN mismatch,
+// wraps the error with the application protocols offered by the client.
+func (interceptor *alpnRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := interceptor.transport.RoundTrip(req)
+	if err == nil {
+		return resp, nil
+	}
+
+	var offered []string
+	// The transport populates the offered protocols when it is first used, so this must be read after the RoundTrip.
+	if interceptor.transport.TLSClientConfig != nil {
+		offered = interceptor.transport.TLSClientConfig.NextProtos
+	}
+
+	switch {
+	case strings.Contains(err.Error(), "tls: no application protocol"):
+		return nil, fmt.Errorf("TLS handshake with %q failed: no mutually supported application protocol, client offered %q but the server accepted none of them: %w", req.URL.Host, offered, err)
+	case strings.Contains(err.Error(), "unadvertised ALPN protocol"):
+		return nil, fmt.Errorf("TLS handshake with %q failed: server selected an application protocol the client did not offer, client offered %q: %w", req.URL.Host, offered, err)
+	}
+	return nil, err
+}
+
 // HTTPServerSettings defines settings for creating an HTTP server.
 type HTTPServerSettings struct {
 	// Endpoint configures the listening address for the server.
@@ -187,11 +255,37 @@ type HTTPServerSettings struct {
 
 	// CORS configures the server for HTTP cross-origin resource sharing (CORS).
 	CORS *CORSSettings `mapstructure:"cors,omitempty"`
+
+	// TCPKeepAlive controls the TCP keep-alive probes on accepted connections, detecting and
+	// reaping idle or dead client connections. If not set, keep-alive is enabled, as done by
+	// Go by default. Setting it to false disables keep-alive. (optional)
+	TCPKeepAlive *bool `mapstructure:"tcp_keepalive,omitempty"`
+
+	// TCPKeepAlivePeriod sets the keep-alive period, i.e. how long a connection stays idle before
+	// keep-alive probes are sent. Ignored when TCPKeepAlive is false. If not set, Go's default of
+	// 15 seconds is used. (optional)
+	TCPKeepAlivePeriod time.Duration `mapstructure:"tcp_keepalive_period"`
+
+	// AccessLog configures the structured access logs emitted for incoming requests. (optional)
+	AccessLog *AccessLogSettings `mapstructure:"access_log,omitempty"`
+
+	// MaxHeaderBytes sets the approximate maximum size, in bytes, of the request headers, including the
+	// request line, as enforced by http.Server.MaxHeaderBytes. The limit isn't exact: net/http allows an
+	// additional 4096 bytes of slack before rejecting a request with a 431 status code. If not set,
+	// http.DefaultMaxHeaderBytes is used. (optional)
+	MaxHeaderBytes int `mapstructure:"max_header_bytes,omitempty"`
+
+	// MaxHeaderCount sets the maximum number of header fields a request may carry. Requests exceeding
+	// it are rejected with a 431 status code. If not set, the number of header fields isn't limited. (optional)
+	MaxHeaderCount int `mapstructure:"max_header_count,omitempty"`
 }
 
 // ToListener creates a net.Listener.
 func (hss *HTTPServerSettings) ToListener() (net.Listener, error) {
-	listener, err := net.Listen("tcp", hss.Endpoint)
+	lc := net.ListenConfig{
+		KeepAlive: hss.keepAlive(),
+	}
+	listener, err := lc.Listen(context.Background(), "tcp", hss.Endpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +301,15 @@ func (hss *HTTPServerSettings) ToListener() (net.Listener, error) {
 	return listener, nil
 }
 
+// keepAlive returns the keep-alive period of the accepted connections, as expected by net.ListenConfig:
+// negative when disabled, and 0 for Go's default period.
+func (hss *HTTPServerSettings) keepAlive() time.Duration {
+	if hss.TCPKeepAlive != nil && !*hss.TCPKeepAlive {
+		return -1
+	}
+	return hss.TCPKeepAlivePeriod
+}
+
 // toServerOptions has options that change the behavior of the HTTP server
 // returned by HTTPServerSettings.ToServer().
 type toServerOptions struct {
@@ -266,8 +369,23 @@ func (hss *HTTPServerSettings) ToServer(_ component.Host, settings component.Tel
 		next: handler,
 	}
 
+	if hss.MaxHeaderCount > 0 {
+		handler = &headerCountLimitHandler{
+			next:     handler,
+			maxCount: hss.MaxHeaderCount,
+		}
+	}
+
+	if hss.AccessLog != nil && hss.AccessLog.Enabled {
+		if err := hss.AccessLog.Validate(); err != nil {
+			return nil, err
+		}
+		handler = newAccessLogHandler(handler, settings.Logger, hss.AccessLog)
+	}
+
 	return &http.Server{
-		Handler: handler,
+		Handler:        handler,
+		MaxHeaderBytes: hss.MaxHeaderBytes,
 	}, nil
 }
 
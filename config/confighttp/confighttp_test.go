@@ -15,6 +15,7 @@
 package confighttp
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -724,3 +725,111 @@ func TestContextWithClient(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPServerSettingsKeepAlive(t *testing.T) {
+	enabled, disabled := true, false
+	testCases := []struct {
+		desc     string
+		settings HTTPServerSettings
+		expected time.Duration
+	}{
+		{
+			desc:     "Go default when unset",
+			expected: 0,
+		},
+		{
+			desc:     "Go default period when enabled",
+			settings: HTTPServerSettings{TCPKeepAlive: &enabled},
+			expected: 0,
+		},
+		{
+			desc:     "custom period",
+			settings: HTTPServerSettings{TCPKeepAlivePeriod: 30 * time.Second},
+			expected: 30 * time.Second,
+		},
+		{
+			desc:     "disabled",
+			settings: HTTPServerSettings{TCPKeepAlive: &disabled, TCPKeepAlivePeriod: 30 * time.Second},
+			expected: -1,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assert.Equal(t, tC.expected, tC.settings.keepAlive())
+		})
+	}
+}
+
+func TestHTTPClientALPNDiagnostics(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		NextProtos: []string{"custom/1.0"},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	testCases := []struct {
+		desc            string
+		alpnDiagnostics bool
+	}{
+		{
+			desc:            "with diagnostics",
+			alpnDiagnostics: true,
+		},
+		{
+			desc:            "without diagnostics",
+			alpnDiagnostics: false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			hcs := HTTPClientSettings{
+				Endpoint: server.URL,
+				TLSSetting: configtls.TLSClientSetting{
+					InsecureSkipVerify: true,
+				},
+				ALPNDiagnostics: tC.alpnDiagnostics,
+			}
+			client, err := hcs.ToClient(map[config.ComponentID]component.Extension{})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, hcs.Endpoint, nil)
+			require.NoError(t, err)
+			_, err = client.Do(req)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "no application protocol")
+			if tC.alpnDiagnostics {
+				assert.Contains(t, err.Error(), "no mutually supported application protocol")
+				assert.Contains(t, err.Error(), `client offered ["h2" "http/1.1"]`)
+			} else {
+				assert.NotContains(t, err.Error(), "client offered")
+			}
+		})
+	}
+}
+
+func TestHTTPClientALPNDiagnosticsSuccess(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	hcs := HTTPClientSettings{
+		Endpoint: server.URL,
+		TLSSetting: configtls.TLSClientSetting{
+			InsecureSkipVerify: true,
+		},
+		ALPNDiagnostics: true,
+	}
+	client, err := hcs.ToClient(map[config.ComponentID]component.Extension{})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, hcs.Endpoint, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
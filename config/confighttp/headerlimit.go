@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
+
+import (
+	"net/http"
+)
+
+var _ http.Handler = (*headerCountLimitHandler)(nil)
+
+// headerCountLimitHandler is an http.Handler rejecting the requests carrying more header fields than allowed,
+// before they reach the next handler.
+type headerCountLimitHandler struct {
+	next     http.Handler
+	maxCount int
+}
+
+// ServeHTTP responds with a 431 status code when the request has too many header fields, or serves it with the next
+// handler otherwise. Each value of a header is counted as a separate field.
+func (h *headerCountLimitHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	count := 0
+	for _, values := range req.Header {
+		count += len(values)
+	}
+	if count > h.maxCount {
+		http.Error(w, http.StatusText(http.StatusRequestHeaderFieldsTooLarge), http.StatusRequestHeaderFieldsTooLarge)
+		return
+	}
+	h.next.ServeHTTP(w, req)
+}
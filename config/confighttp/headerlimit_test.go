@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestHeaderCountLimitHandler(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		headers  int
+		values   int
+		expected int
+	}{
+		{
+			desc:     "within limit",
+			headers:  5,
+			values:   1,
+			expected: http.StatusOK,
+		},
+		{
+			desc:     "too many headers",
+			headers:  6,
+			values:   1,
+			expected: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			desc:     "too many values",
+			headers:  2,
+			values:   3,
+			expected: http.StatusRequestHeaderFieldsTooLarge,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			served := false
+			h := &headerCountLimitHandler{
+				next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					served = true
+				}),
+				maxCount: 5,
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			for i := 0; i < tC.headers; i++ {
+				for j := 0; j < tC.values; j++ {
+					req.Header.Add(fmt.Sprintf("X-Header-%d", i), "value")
+				}
+			}
+			rec := httptest.NewRecorder()
+
+			// test
+			h.ServeHTTP(rec, req)
+
+			// verify
+			assert.Equal(t, tC.expected, rec.Code)
+			assert.Equal(t, tC.expected == http.StatusOK, served)
+		})
+	}
+}
+
+func TestServerHeaderLimits(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		settings HTTPServerSettings
+		headers  http.Header
+		expected int
+	}{
+		{
+			desc:     "no limits",
+			headers:  manyHeaders(50, 10),
+			expected: http.StatusOK,
+		},
+		{
+			desc:     "header bytes within limit",
+			settings: HTTPServerSettings{MaxHeaderBytes: 1024},
+			headers:  manyHeaders(1, 10),
+			expected: http.StatusOK,
+		},
+		{
+			desc:     "header bytes exceeded",
+			settings: HTTPServerSettings{MaxHeaderBytes: 1024},
+			headers:  manyHeaders(10, 2048),
+			expected: http.StatusRequestHeaderFieldsTooLarge,
+		},
+		{
+			desc:     "header count within limit",
+			settings: HTTPServerSettings{MaxHeaderCount: 20},
+			headers:  manyHeaders(10, 10),
+			expected: http.StatusOK,
+		},
+		{
+			desc:     "header count exceeded",
+			settings: HTTPServerSettings{MaxHeaderCount: 20},
+			headers:  manyHeaders(50, 10),
+			expected: http.StatusRequestHeaderFieldsTooLarge,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			hss := tC.settings
+			hss.Endpoint = "localhost:0"
+			ln, err := hss.ToListener()
+			require.NoError(t, err)
+
+			srv, err := hss.ToServer(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(),
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			require.NoError(t, err)
+			go func() {
+				_ = srv.Serve(ln)
+			}()
+			defer srv.Close()
+
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%s/", ln.Addr().String()), nil)
+			require.NoError(t, err)
+			req.Header = tC.headers
+
+			// test
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+
+			// verify
+			assert.Equal(t, tC.expected, resp.StatusCode)
+		})
+	}
+}
+
+// manyHeaders returns the given number of headers, each with a value of the given size.
+func manyHeaders(count int, size int) http.Header {
+	headers := http.Header{}
+	for i := 0; i < count; i++ {
+		headers.Set(fmt.Sprintf("X-Header-%d", i), strings.Repeat("a", size))
+	}
+	return headers
+}
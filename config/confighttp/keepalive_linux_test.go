@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package confighttp
+
+import (
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToListenerTCPKeepAlive(t *testing.T) {
+	enabled, disabled := true, false
+	testCases := []struct {
+		desc            string
+		keepAlive       *bool
+		period          time.Duration
+		expectedEnabled bool
+		expectedIdle    int
+	}{
+		{
+			desc:            "unset",
+			expectedEnabled: true,
+			expectedIdle:    15,
+		},
+		{
+			desc:            "enabled with period",
+			keepAlive:       &enabled,
+			period:          10 * time.Second,
+			expectedEnabled: true,
+			expectedIdle:    10,
+		},
+		{
+			desc:            "disabled",
+			keepAlive:       &disabled,
+			period:          10 * time.Second,
+			expectedEnabled: false,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// prepare
+			hss := &HTTPServerSettings{
+				Endpoint:           "localhost:0",
+				TCPKeepAlive:       tC.keepAlive,
+				TCPKeepAlivePeriod: tC.period,
+			}
+			ln, err := hss.ToListener()
+			require.NoError(t, err)
+			defer ln.Close()
+
+			addr := ln.Addr().String()
+			go func() {
+				conn, derr := net.Dial("tcp", addr)
+				if derr == nil {
+					conn.Close()
+				}
+			}()
+
+			// test
+			conn, err := ln.Accept()
+			require.NoError(t, err)
+			defer conn.Close()
+
+			// verify
+			tcpConn, ok := conn.(*net.TCPConn)
+			require.True(t, ok)
+			rawConn, err := tcpConn.SyscallConn()
+			require.NoError(t, err)
+
+			var keepAlive, idle int
+			var sockErr error
+			require.NoError(t, rawConn.Control(func(fd uintptr) {
+				keepAlive, sockErr = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_KEEPALIVE)
+				if sockErr != nil {
+					return
+				}
+				idle, sockErr = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPIDLE)
+			}))
+			require.NoError(t, sockErr)
+
+			assert.Equal(t, tC.expectedEnabled, keepAlive != 0)
+			if tC.expectedEnabled {
+				assert.Equal(t, tC.expectedIdle, idle)
+			}
+		})
+	}
+}
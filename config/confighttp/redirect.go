@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
+
+import (
+	"net/http"
+)
+
+// sensitiveHeaders are always removed from requests redirected to a different host.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Cookie2",
+	"WWW-Authenticate",
+}
+
+var _ http.RoundTripper = (*redirectHeaderStripper)(nil)
+
+// redirectHeaderStripper is a RoundTripper removing sensitive headers from requests that result from a
+// redirect to a different host. It must wrap the transport directly, so that it sees the headers added by
+// the other round trippers, such as the authenticators.
+type redirectHeaderStripper struct {
+	transport http.RoundTripper
+	headers   []string
+}
+
+func newRedirectHeaderStripper(transport http.RoundTripper, configured map[string]string) *redirectHeaderStripper {
+	headers := append([]string{}, sensitiveHeaders...)
+	for k := range configured {
+		headers = append(headers, k)
+	}
+	return &redirectHeaderStripper{
+		transport: transport,
+		headers:   headers,
+	}
+}
+
+// RoundTrip removes the sensitive headers when the request was redirected from a different host.
+func (rt *redirectHeaderStripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if origin := originalRequest(req); origin != req && origin.URL.Host != req.URL.Host {
+		// RoundTrippers must not modify the given request.
+		req = req.Clone(req.Context())
+		for _, h := range rt.headers {
+			req.Header.Del(h)
+		}
+	}
+	return rt.transport.RoundTrip(req)
+}
+
+// originalRequest follows the chain of redirects that led to the given request, returning the first request.
+func originalRequest(req *http.Request) *http.Request {
+	for req.Response != nil && req.Response.Request != nil {
+		req = req.Response.Request
+	}
+	return req
+}
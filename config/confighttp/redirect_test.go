@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+)
+
+func TestFollowRedirects(t *testing.T) {
+	var targetHeaders http.Header
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targetHeaders = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	var source *httptest.Server
+	source = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cross-host":
+			http.Redirect(w, r, target.URL, http.StatusFound)
+		case "/same-host":
+			http.Redirect(w, r, source.URL+"/final", http.StatusFound)
+		default:
+			targetHeaders = r.Header.Clone()
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer source.Close()
+
+	enabled := true
+	disabled := false
+	testCases := []struct {
+		desc            string
+		followRedirects *bool
+		path            string
+		expectedStatus  int
+		expectedHeaders bool
+	}{
+		{
+			desc:            "disabled",
+			followRedirects: &disabled,
+			path:            "/cross-host",
+			expectedStatus:  http.StatusFound,
+		},
+		{
+			desc:            "enabled cross host",
+			followRedirects: &enabled,
+			path:            "/cross-host",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: false,
+		},
+		{
+			desc:            "enabled same host",
+			followRedirects: &enabled,
+			path:            "/same-host",
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			targetHeaders = nil
+			hcs := HTTPClientSettings{
+				Endpoint: source.URL + tC.path,
+				Headers: map[string]string{
+					"X-Api-Key": "secret",
+				},
+				FollowRedirects: tC.followRedirects,
+			}
+			client, err := hcs.ToClient(map[config.ComponentID]component.Extension{})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, hcs.Endpoint, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer token")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			assert.NoError(t, resp.Body.Close())
+			assert.Equal(t, tC.expectedStatus, resp.StatusCode)
+
+			if tC.expectedStatus != http.StatusOK {
+				assert.Nil(t, targetHeaders)
+				return
+			}
+			require.NotNil(t, targetHeaders)
+			if tC.expectedHeaders {
+				assert.Equal(t, "Bearer token", targetHeaders.Get("Authorization"))
+				assert.Equal(t, "secret", targetHeaders.Get("X-Api-Key"))
+			} else {
+				assert.Empty(t, targetHeaders.Get("Authorization"))
+				assert.Empty(t, targetHeaders.Get("X-Api-Key"))
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
+
+import (
+	"io"
+	"net/http"
+)
+
+var _ http.RoundTripper = (*trailerRoundTripper)(nil)
+
+// trailerRoundTripper is a RoundTripper invoking a validator on responses once their trailers have been read.
+type trailerRoundTripper struct {
+	transport http.RoundTripper
+	validator func(resp *http.Response) error
+}
+
+// RoundTrip sends the request, wrapping the response body so that the validator runs when the body is exhausted.
+func (rt *trailerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &trailerBody{
+		ReadCloser: resp.Body,
+		resp:       resp,
+		validator:  rt.validator,
+	}
+	return resp, nil
+}
+
+// trailerBody is an io.ReadCloser calling the validator when the end of the body is reached.
+// Trailers are only populated by the transport after the body has been fully read.
+type trailerBody struct {
+	io.ReadCloser
+	resp      *http.Response
+	validator func(resp *http.Response) error
+	validated bool
+	err       error
+}
+
+func (b *trailerBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF && !b.validated {
+		b.validated = true
+		if verr := b.validator(b.resp); verr != nil {
+			b.err = verr
+			return n, verr
+		}
+	}
+	return n, err
+}
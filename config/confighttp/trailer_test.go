@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package confighttp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+)
+
+func TestResponseValidatorTrailers(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		status      string
+		expectedErr bool
+	}{
+		{
+			desc:   "success status in trailer",
+			status: "0",
+		},
+		{
+			desc:        "failure status in trailer",
+			status:      "13",
+			expectedErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Trailer", "Grpc-Status")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte("payload"))
+				w.Header().Set("Grpc-Status", tC.status)
+			}))
+			defer server.Close()
+
+			var trailers http.Header
+			hcs := HTTPClientSettings{
+				Endpoint: server.URL,
+				ResponseValidator: func(resp *http.Response) error {
+					trailers = resp.Trailer.Clone()
+					if status := resp.Trailer.Get("Grpc-Status"); status != "0" {
+						return fmt.Errorf("unexpected status %s", status)
+					}
+					return nil
+				},
+			}
+			client, err := hcs.ToClient(map[config.ComponentID]component.Extension{})
+			require.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPost, hcs.Endpoint, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, resp.Body.Close())
+			assert.Equal(t, "payload", string(body))
+			assert.Equal(t, tC.status, trailers.Get("Grpc-Status"))
+			if tC.expectedErr {
+				assert.EqualError(t, err, "unexpected status 13")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -38,12 +38,22 @@ type TLSSetting struct {
 	KeyFile string `mapstructure:"key_file"`
 
 	// MinVersion sets the minimum TLS version that is acceptable.
-	// If not set, TLS 1.0 is used. (optional)
+	// If not set, TLS 1.2 is used. (optional)
 	MinVersion string `mapstructure:"min_version"`
 
 	// MaxVersion sets the maximum TLS version that is acceptable.
 	// If not set, TLS 1.3 is used. (optional)
 	MaxVersion string `mapstructure:"max_version"`
+
+	// CurvePreferences restricts the elliptic curves used in an ECDHE handshake, in order of preference.
+	// Valid values are "X25519", "P-256", "P-384" and "P-521". If not set, the Go defaults are used. (optional)
+	CurvePreferences []string `mapstructure:"curve_preferences"`
+
+	// CipherSuites restricts the cipher suites used for TLS 1.0 to 1.2, by their names as defined by crypto/tls,
+	// e.g. "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256". Only the suites returned by tls.CipherSuites are accepted, the
+	// insecure ones, such as RC4 or 3DES based suites, being rejected. TLS 1.3 cipher suites aren't configurable.
+	// If not set, the Go defaults are used. (optional)
+	CipherSuites []string `mapstructure:"cipher_suites"`
 }
 
 // TLSClientSetting contains TLS configurations that are specific to client
@@ -114,20 +124,35 @@ func (c TLSSetting) loadTLSConfig() (*tls.Config, error) {
 		certificates = append(certificates, tlsCert)
 	}
 
-	minTLS, err := convertVersion(c.MinVersion)
+	minTLS, err := convertVersion(c.MinVersion, defaultMinTLSVersion)
 	if err != nil {
 		return nil, fmt.Errorf("invalid TLS min_version: %w", err)
 	}
-	maxTLS, err := convertVersion(c.MaxVersion)
+	maxTLS, err := convertVersion(c.MaxVersion, defaultMaxTLSVersion)
 	if err != nil {
 		return nil, fmt.Errorf("invalid TLS max_version: %w", err)
 	}
+	if maxTLS != defaultMaxTLSVersion && maxTLS < minTLS {
+		return nil, fmt.Errorf("invalid TLS max_version: %q is lower than min_version %q", versionName(maxTLS), versionName(minTLS))
+	}
+
+	curves, err := convertCurvePreferences(c.CurvePreferences)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS curve_preferences: %w", err)
+	}
+
+	cipherSuites, err := convertCipherSuites(c.CipherSuites)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS cipher_suites: %w", err)
+	}
 
 	return &tls.Config{
-		RootCAs:      certPool,
-		Certificates: certificates,
-		MinVersion:   minTLS,
-		MaxVersion:   maxTLS,
+		RootCAs:          certPool,
+		Certificates:     certificates,
+		MinVersion:       minTLS,
+		MaxVersion:       maxTLS,
+		CurvePreferences: curves,
+		CipherSuites:     cipherSuites,
 	}, nil
 }
 
@@ -176,9 +201,15 @@ func (c TLSServerSetting) LoadTLSConfig() (*tls.Config, error) {
 	return tlsCfg, nil
 }
 
-func convertVersion(v string) (uint16, error) {
+const (
+	defaultMinTLSVersion = tls.VersionTLS12
+	// defaultMaxTLSVersion leaves the maximum version up to crypto/tls, currently TLS 1.3.
+	defaultMaxTLSVersion = 0
+)
+
+func convertVersion(v string, defaultVersion uint16) (uint16, error) {
 	if v == "" {
-		return tls.VersionTLS12, nil // default
+		return defaultVersion, nil
 	}
 	val, ok := tlsVersions[v]
 	if !ok {
@@ -187,9 +218,72 @@ func convertVersion(v string) (uint16, error) {
 	return val, nil
 }
 
+// versionName returns the name of the given TLS version, as accepted by convertVersion.
+func versionName(v uint16) string {
+	for name, val := range tlsVersions {
+		if val == v {
+			return name
+		}
+	}
+	return fmt.Sprintf("0x%04x", v)
+}
+
 var tlsVersions = map[string]uint16{
 	"1.0": tls.VersionTLS10,
 	"1.1": tls.VersionTLS11,
 	"1.2": tls.VersionTLS12,
 	"1.3": tls.VersionTLS13,
 }
+
+func convertCurvePreferences(names []string) ([]tls.CurveID, error) {
+	if len(names) == 0 {
+		return nil, nil // use the Go defaults
+	}
+	curves := make([]tls.CurveID, 0, len(names))
+	for _, name := range names {
+		curve, ok := tlsCurves[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve: %q", name)
+		}
+		curves = append(curves, curve)
+	}
+	return curves, nil
+}
+
+var tlsCurves = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P-256":  tls.CurveP256,
+	"P-384":  tls.CurveP384,
+	"P-521":  tls.CurveP521,
+}
+
+func convertCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil // use the Go defaults
+	}
+	suites := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, err := lookupCipherSuite(name)
+		if err != nil {
+			return nil, err
+		}
+		suites = append(suites, id)
+	}
+	return suites, nil
+}
+
+// lookupCipherSuite returns the ID of the cipher suite with the given name. The cipher suites with known security
+// issues, listed by tls.InsecureCipherSuites, are rejected.
+func lookupCipherSuite(name string) (uint16, error) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, nil
+		}
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == name {
+			return 0, fmt.Errorf("insecure cipher suite: %q", name)
+		}
+	}
+	return 0, fmt.Errorf("unsupported cipher suite: %q", name)
+}
@@ -15,6 +15,8 @@
 package configtls
 
 import (
+	"crypto/tls"
+	"fmt"
 	"path"
 	"testing"
 
@@ -115,6 +117,28 @@ func TestOptionsToConfig(t *testing.T) {
 				MaxVersion: "1.2",
 			},
 		},
+		{
+			name: "should pass with the same min and max version",
+			options: TLSSetting{
+				MinVersion: "1.2",
+				MaxVersion: "1.2",
+			},
+		},
+		{
+			name: "should fail with max version lower than min version",
+			options: TLSSetting{
+				MinVersion: "1.3",
+				MaxVersion: "1.2",
+			},
+			expectError: `invalid TLS max_version: "1.2" is lower than min_version "1.3"`,
+		},
+		{
+			name: "should fail with max version lower than the default min version",
+			options: TLSSetting{
+				MaxVersion: "1.1",
+			},
+			expectError: `invalid TLS max_version: "1.1" is lower than min_version "1.2"`,
+		},
 		{
 			name: "should pass with invalid min",
 			options: TLSSetting{
@@ -129,6 +153,47 @@ func TestOptionsToConfig(t *testing.T) {
 			},
 			expectError: "invalid TLS max_",
 		},
+
+		{
+			name: "should pass with valid curve preferences",
+			options: TLSSetting{
+				CurvePreferences: []string{"X25519", "P-256"},
+			},
+		},
+		{
+			name: "should fail with invalid curve preferences",
+			options: TLSSetting{
+				CurvePreferences: []string{"P-256", "P-192"},
+			},
+			expectError: `invalid TLS curve_preferences: unsupported curve: "P-192"`,
+		},
+		{
+			name: "should pass with valid cipher suites",
+			options: TLSSetting{
+				CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+			},
+		},
+		{
+			name: "should pass with cipher suites and TLS 1.3",
+			options: TLSSetting{
+				MinVersion:   "1.3",
+				CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+			},
+		},
+		{
+			name: "should fail with invalid cipher suites",
+			options: TLSSetting{
+				CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_FAKE_WITH_NULL"},
+			},
+			expectError: `invalid TLS cipher_suites: unsupported cipher suite: "TLS_FAKE_WITH_NULL"`,
+		},
+		{
+			name: "should fail with insecure cipher suites",
+			options: TLSSetting{
+				CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_RSA_WITH_RC4_128_SHA"},
+			},
+			expectError: `invalid TLS cipher_suites: insecure cipher suite: "TLS_RSA_WITH_RC4_128_SHA"`,
+		},
 	}
 
 	for _, test := range tests {
@@ -201,3 +266,84 @@ func TestLoadTLSServerConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tlsCfg)
 }
+
+func TestCurvePreferences(t *testing.T) {
+	tlsSetting := TLSSetting{
+		CurvePreferences: []string{"P-384", "X25519", "P-256", "P-521"},
+	}
+	tlsCfg, err := tlsSetting.loadTLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []tls.CurveID{tls.CurveP384, tls.X25519, tls.CurveP256, tls.CurveP521}, tlsCfg.CurvePreferences)
+
+	tlsSetting = TLSSetting{}
+	tlsCfg, err = tlsSetting.loadTLSConfig()
+	require.NoError(t, err)
+	assert.Nil(t, tlsCfg.CurvePreferences)
+}
+
+func TestTLSVersions(t *testing.T) {
+	tests := []struct {
+		name        string
+		minVersion  string
+		maxVersion  string
+		expectedMin uint16
+		expectedMax uint16
+	}{
+		{
+			name:        "defaults",
+			expectedMin: tls.VersionTLS12,
+		},
+		{
+			name:        "only TLS 1.3",
+			minVersion:  "1.3",
+			expectedMin: tls.VersionTLS13,
+		},
+		{
+			name:        "pinned to TLS 1.2",
+			minVersion:  "1.2",
+			maxVersion:  "1.2",
+			expectedMin: tls.VersionTLS12,
+			expectedMax: tls.VersionTLS12,
+		},
+		{
+			name:        "from TLS 1.0 to 1.3",
+			minVersion:  "1.0",
+			maxVersion:  "1.3",
+			expectedMin: tls.VersionTLS10,
+			expectedMax: tls.VersionTLS13,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tlsSetting := TLSSetting{
+				MinVersion: test.minVersion,
+				MaxVersion: test.maxVersion,
+			}
+			tlsCfg, err := tlsSetting.loadTLSConfig()
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedMin, tlsCfg.MinVersion)
+			assert.Equal(t, test.expectedMax, tlsCfg.MaxVersion)
+		})
+	}
+}
+
+func TestCipherSuites(t *testing.T) {
+	tlsSetting := TLSSetting{
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+	}
+	tlsCfg, err := tlsSetting.loadTLSConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, tlsCfg.CipherSuites)
+
+	for _, suite := range tls.InsecureCipherSuites() {
+		tlsSetting = TLSSetting{CipherSuites: []string{suite.Name}}
+		_, err = tlsSetting.loadTLSConfig()
+		assert.EqualError(t, err, fmt.Sprintf("invalid TLS cipher_suites: insecure cipher suite: %q", suite.Name))
+	}
+
+	tlsSetting = TLSSetting{}
+	tlsCfg, err = tlsSetting.loadTLSConfig()
+	require.NoError(t, err)
+	assert.Nil(t, tlsCfg.CipherSuites)
+}